@@ -0,0 +1,98 @@
+package main
+
+import (
+	"bufio"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"go-sudoku-solver/internal/cliio"
+	"go-sudoku-solver/sudoku"
+)
+
+func main() {
+	in := flag.String("in", "", "read puzzles from `path` instead of stdin")
+	out := flag.String("out", "", "write solutions to `path` instead of stdout")
+	stats := flag.Bool("stats", false, "print solver statistics for each puzzle to stderr")
+	flag.Parse()
+
+	input, err := cliio.Open(*in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer input.Close()
+	puzzles := readPuzzles(input)
+
+	output, err := cliio.Create(*out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	writer := bufio.NewWriter(output)
+
+	start := time.Now()
+	solved := 0
+
+	for i, puzzleStr := range puzzles {
+		var result string
+		puzzle, perr := sudoku.ParsePuzzle(puzzleStr)
+		if perr == nil {
+			perr = puzzle.Validate()
+		}
+		if perr != nil {
+			fmt.Fprintln(os.Stderr, perr)
+			result = "Invalid puzzle"
+		} else {
+			st, ok := puzzle.SolveStats()
+			if *stats {
+				fmt.Fprintf(os.Stderr, "puzzle %d: %v\n", i+1, st)
+			}
+			result = "No solution found"
+			if ok {
+				result = puzzle.ToString()
+				solved++
+			}
+		}
+		// Stop at the first failed write rather than solving the rest
+		// for nothing; bufio.Writer would discard them anyway.
+		if _, err = writer.WriteString(result + "\n"); err != nil {
+			break
+		}
+	}
+
+	if cerr := cliio.Close(writer, output); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		cliio.Fail(*out, err)
+	}
+
+	duration := time.Since(start)
+	fmt.Fprintf(os.Stderr, "Solved %d puzzles in %v\n", solved, duration)
+	if len(puzzles) == 0 {
+		fmt.Fprintln(os.Stderr, "no valid puzzles found")
+	} else {
+		fmt.Fprintf(os.Stderr, "Average time per puzzle: %v\n", duration/time.Duration(len(puzzles)))
+	}
+}
+
+// readPuzzles returns the puzzles in r, given either one per line or as
+// grid blocks, warning on stderr about input that is neither.
+func readPuzzles(r io.Reader) []string {
+	var puzzles []string
+	scanner := sudoku.NewScanner(r)
+	scanner.OnSkip = func(line int, err error) {
+		fmt.Fprintf(os.Stderr, "skipping line %d: %v\n", line, err)
+	}
+	for scanner.Scan() {
+		puzzles = append(puzzles, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "reading puzzles:", err)
+		os.Exit(1)
+	}
+	return puzzles
+}
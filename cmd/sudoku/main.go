@@ -0,0 +1,422 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go-sudoku-solver/internal/cliio"
+	"go-sudoku-solver/sudoku"
+)
+
+func main() {
+	in := flag.String("in", "", "read puzzles from `path` instead of stdin")
+	out := flag.String("out", "", "write solutions to `path` instead of stdout")
+	pretty := flag.Bool("pretty", false, "write solutions as grids with box borders")
+	workers := flag.Int("workers", 0, "number of solver goroutines (0 = one per CPU)")
+	check := flag.Bool("check", false, "warn about 9x9 puzzles with too few clues to be unique")
+	count := flag.Bool("count", false, "print unique, multiple or none per puzzle instead of solving it")
+	col := flag.String("col", "", "read CSV input and take puzzles from the column with this header")
+	solCol := flag.String("solcol", "", "with -col, compare solutions against the CSV column with this header")
+	hardest := flag.Int("hardest", 0, "after solving, list the `k` slowest puzzles with their clue and node counts")
+	progress := flag.Int("progress", 0, "report on stderr every `n` puzzles solved")
+	verify := flag.Bool("verify", false, "check every solution against its puzzle and exit non-zero if any is wrong")
+	mode := flag.String("mode", "first", "`first` solution per puzzle, the solution count, or all solutions")
+	limit := flag.Int("limit", 1000, "with -mode count or all, stop after `n` solutions per puzzle")
+	seed := flag.Int64("seed", 0, "try candidates in a random order seeded from `n` and each puzzle's position (0 = lowest first)")
+	variant := flag.String("variant", "classic", "board `kind`: classic, x, windoku or jigsaw")
+	regions := flag.String("regions", "", "with -variant jigsaw, read the regions from `path`: one row per line, one region digit per cell")
+	labels := flag.Bool("labels", false, "prefix each result with the header line of its puzzle, such as \"Grid 01\"")
+	timeout := flag.Duration("timeout", 0, "give up on any puzzle not solved within `d`, such as 5s, and write \"timeout\" for it (0 = no limit)")
+	flag.Parse()
+
+	input, err := cliio.Open(*in)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	defer input.Close()
+	var puzzles, names, expected []string
+	if *col != "" {
+		puzzles, expected = readCSV(input, *col, *solCol)
+	} else {
+		puzzles, names = readPuzzles(input)
+	}
+	if !*labels {
+		names = nil
+	}
+	if *check {
+		checkClues(puzzles)
+	}
+	parse, err := variantParser(*variant, *regions)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+	set := map[string]bool{}
+	flag.Visit(func(f *flag.Flag) { set[f.Name] = true })
+	if *count {
+		if set["mode"] && *mode != "count" {
+			fmt.Fprintf(os.Stderr, "-count means -mode count, not -mode %s\n", *mode)
+			os.Exit(2)
+		}
+		if set["limit"] {
+			fmt.Fprintln(os.Stderr, "-count stops at a second solution, so it takes no -limit")
+			os.Exit(2)
+		}
+		*mode, *limit = "count", 2
+	}
+	if *mode != "first" && *mode != "count" && *mode != "all" {
+		fmt.Fprintf(os.Stderr, "unknown -mode %q; want first, count or all\n", *mode)
+		os.Exit(2)
+	}
+	if *mode != "first" {
+		// The count and all modes run one puzzle at a time without the
+		// batch solver, which is what these flags configure.
+		for _, name := range []string{"workers", "timeout", "seed", "progress", "verify", "solcol"} {
+			if set[name] {
+				fmt.Fprintf(os.Stderr, "-%s only applies to -mode first\n", name)
+				os.Exit(2)
+			}
+		}
+	}
+	if *hardest > 0 && *mode != "first" {
+		fmt.Fprintln(os.Stderr, "-hardest reports solve times, so it needs -mode first")
+		os.Exit(2)
+	}
+
+	output, err := cliio.Create(*out)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	writer := bufio.NewWriter(output)
+
+	start := time.Now()
+	failed, mismatched, timedOut := 0, 0, 0
+	var timings []timing
+	switch {
+	case *mode == "count":
+		format := strconv.Itoa
+		if *count {
+			format = countWord
+		}
+		err = writeSolutionCounts(writer, puzzles, names, parse, *limit, format)
+	case *mode == "all":
+		err = writeAllSolutions(writer, puzzles, names, parse, *limit, *pretty)
+	default:
+		// Solutions are written as they finish, in input order.
+		report := func(n int) {
+			elapsed := time.Since(start)
+			fmt.Fprintf(os.Stderr, "%d puzzles solved, %.0f puzzles/s\n", n, float64(n)/elapsed.Seconds())
+		}
+		opts := sudoku.BatchOptions{Workers: *workers, Every: *progress, Progress: report, Setup: parse.setup, Seed: *seed, Timeout: *timeout}
+		if *hardest > 0 {
+			opts.Stats = func(i int, stats sudoku.Stats) {
+				timings = append(timings, timing{index: i, stats: stats})
+			}
+		}
+		err = sudoku.SolvePuzzlesErr(puzzles, opts, func(i int, solution string, serr error) error {
+			solved := serr == nil
+			if solved && *verify && !verifySolution(parse, puzzles[i], solution) {
+				fmt.Fprintf(os.Stderr, "puzzle %d: solution does not check out\n", i+1)
+				failed++
+			}
+			if expected != nil && solution != expected[i] {
+				mismatched++
+			}
+			switch {
+			case errors.Is(serr, context.DeadlineExceeded):
+				solution = "timeout"
+				timedOut++
+				if i < len(timings) {
+					// Stats runs just before emit, in input order.
+					timings[i].timedOut = true
+				}
+			case !solved:
+				solution = "No solution found"
+			case *pretty:
+				if p, err := sudoku.ParsePuzzle(solution); err == nil {
+					solution = p.Pretty()
+				}
+			}
+			_, err := writer.WriteString(labelled(names, i, solution) + "\n")
+			return err
+		})
+	}
+	if cerr := cliio.Close(writer, output); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		cliio.Fail(*out, err)
+	}
+	duration := time.Since(start)
+	if timedOut > 0 {
+		fmt.Fprintf(os.Stderr, "%d puzzles timed out after %v\n", timedOut, *timeout)
+	}
+	if expected != nil {
+		fmt.Fprintf(os.Stderr, "%d of %d solutions differ from column %q\n", mismatched, len(puzzles), *solCol)
+	}
+	if failed > 0 {
+		fmt.Fprintf(os.Stderr, "%d solutions failed verification\n", failed)
+		os.Exit(1)
+	}
+
+	fmt.Fprintf(os.Stderr, "Solved %d puzzles in %v\n", len(puzzles), duration)
+	if len(puzzles) == 0 {
+		fmt.Fprintln(os.Stderr, "no valid puzzles found")
+	} else {
+		fmt.Fprintf(os.Stderr, "Average time per puzzle: %v\n", duration/time.Duration(len(puzzles)))
+	}
+	if *hardest > 0 {
+		reportHardest(puzzles, parse, timings, *hardest)
+	}
+}
+
+// countWord describes a solution count found with a limit of 2, as -count
+// prints it.
+func countWord(n int) string {
+	switch n {
+	case 0:
+		return "none"
+	case 1:
+		return "unique"
+	}
+	return "multiple"
+}
+
+// writeSolutionCounts writes the number of solutions of each puzzle, up to
+// limit, as format renders it. Puzzles that do not parse or break the rules
+// have none.
+func writeSolutionCounts(w io.Writer, puzzles, names []string, parse parser, limit int, format func(int) string) error {
+	for i, puzzle := range puzzles {
+		n := 0
+		if p, err := parse.parse(puzzle); err == nil && p.Validate() == nil {
+			n = p.CountSolutions(limit)
+		}
+		if _, err := fmt.Fprintln(w, labelled(names, i, format(n))); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeAllSolutions writes up to limit solutions of each puzzle, one per
+// line or as grids if pretty is set, with a blank line after each puzzle's
+// solutions.
+func writeAllSolutions(w io.Writer, puzzles, names []string, parse parser, limit int, pretty bool) error {
+	for i, puzzle := range puzzles {
+		var lines []string
+		if p, err := parse.parse(puzzle); err == nil && p.Validate() == nil {
+			for _, s := range p.SolveAll(limit) {
+				if pretty {
+					lines = append(lines, s.String())
+				} else {
+					lines = append(lines, s.ToString())
+				}
+			}
+		}
+		result := "No solution found"
+		if len(lines) > 0 {
+			sep := "\n"
+			if pretty {
+				sep = "\n\n"
+			}
+			result = strings.Join(lines, sep)
+		}
+		if _, err := fmt.Fprintf(w, "%s\n\n", labelled(names, i, result)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// timing is what the batch recorded about solving one puzzle.
+type timing struct {
+	index    int
+	stats    sudoku.Stats
+	timedOut bool
+}
+
+// reportHardest lists on stderr the k slowest of the puzzles the batch
+// solved, using the times and node counts its workers recorded, so nothing
+// is solved a second time. The times are wall-clock inside the workers, so
+// with more workers than CPUs they include time spent waiting to run.
+// Puzzles that do not parse or break the rules are left out.
+func reportHardest(puzzles []string, parse parser, timings []timing, k int) {
+	timings = slices.DeleteFunc(timings, func(t timing) bool {
+		p, err := parse.parse(puzzles[t.index])
+		return err != nil || p.Validate() != nil
+	})
+	sort.SliceStable(timings, func(a, b int) bool {
+		return timings[a].stats.Elapsed > timings[b].stats.Elapsed
+	})
+
+	fmt.Fprintf(os.Stderr, "Slowest %d puzzles:\n", min(k, len(timings)))
+	for _, t := range timings[:min(k, len(timings))] {
+		p, _ := parse.parse(puzzles[t.index])
+		note := ""
+		if t.timedOut {
+			note = ", timed out"
+		}
+		fmt.Fprintf(os.Stderr, "puzzle %d: %v, %d clues, %d nodes%s\n", t.index+1, t.stats.Elapsed, p.ClueCount(), t.stats.Nodes, note)
+	}
+}
+
+// verifySolution reports whether solution is a correct completion of
+// puzzle under the variant of parse.
+func verifySolution(parse parser, puzzle, solution string) bool {
+	givens, err := parse.parse(puzzle)
+	if err != nil {
+		return false
+	}
+	solved, err := parse.parse(solution)
+	return err == nil && solved.CheckAgainst(givens)
+}
+
+// parser reads puzzles for the variant chosen on the command line.
+type parser struct {
+	// setup applies the variant to a parsed board.
+	setup func(*sudoku.Puzzle) error
+}
+
+// variantParser returns the parser for the -variant and -regions flags.
+func variantParser(name, regionsPath string) (parser, error) {
+	v, err := sudoku.ParseVariant(name)
+	if err != nil {
+		return parser{}, err
+	}
+	if (v == sudoku.Jigsaw) != (regionsPath != "") {
+		return parser{}, fmt.Errorf("-regions is needed with -variant jigsaw and only then")
+	}
+	if v != sudoku.Jigsaw {
+		return parser{func(p *sudoku.Puzzle) error { return p.SetVariant(v) }}, nil
+	}
+	regions, err := readRegions(regionsPath)
+	if err != nil {
+		return parser{}, err
+	}
+	return parser{func(p *sudoku.Puzzle) error { return p.SetRegions(regions) }}, nil
+}
+
+// parse reads a one-line puzzle and applies the variant to it.
+func (ps parser) parse(puzzle string) (*sudoku.Puzzle, error) {
+	p, err := sudoku.ParsePuzzle(puzzle)
+	if err != nil {
+		return nil, err
+	}
+	if err := ps.setup(p); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// readRegions reads a Jigsaw region map: one line per row, with each cell's
+// region given as a digit from sudoku.DIGITS, starting at 1.
+func readRegions(path string) ([][]int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var regions [][]int
+	for i, line := range strings.Fields(string(data)) {
+		row := make([]int, len(line))
+		for j := range line {
+			n := strings.IndexByte(sudoku.DIGITS, strings.ToUpper(line)[j])
+			if n < 0 {
+				return nil, fmt.Errorf("%s: row %d, column %d: %q is not a region digit", path, i+1, j+1, line[j])
+			}
+			row[j] = n
+		}
+		regions = append(regions, row)
+	}
+	return regions, nil
+}
+
+// labelled prefixes the result for puzzle i with its label, if it has one.
+// A multi-line result starts on the line after the label.
+func labelled(names []string, i int, result string) string {
+	if i >= len(names) || names[i] == "" {
+		return result
+	}
+	if strings.Contains(result, "\n") {
+		return names[i] + ":\n" + result
+	}
+	return names[i] + ": " + result
+}
+
+// checkClues warns on stderr about every classic puzzle with fewer than
+// sudoku.MIN_CLUES givens, since none of them can have a unique solution.
+func checkClues(puzzles []string) {
+	for i, puzzle := range puzzles {
+		p, err := sudoku.ParsePuzzle(puzzle)
+		if err != nil || p.Size() != sudoku.SIZE {
+			continue
+		}
+		if n := p.ClueCount(); n < sudoku.MIN_CLUES {
+			fmt.Fprintf(os.Stderr, "puzzle %d has %d clues; at least %d are needed for a unique solution\n", i+1, n, sudoku.MIN_CLUES)
+		}
+	}
+}
+
+// readPuzzles returns the puzzles in r, given either one per line or as
+// grid blocks, along with the label of each, warning on stderr about input
+// that is neither.
+func readPuzzles(r io.Reader) (puzzles, labels []string) {
+	scanner := sudoku.NewScanner(r)
+	scanner.OnSkip = func(line int, err error) {
+		fmt.Fprintf(os.Stderr, "skipping line %d: %v\n", line, err)
+	}
+	for scanner.Scan() {
+		puzzles = append(puzzles, scanner.Text())
+		labels = append(labels, scanner.Label())
+	}
+	if err := scanner.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, "reading puzzles:", err)
+		os.Exit(1)
+	}
+	return puzzles, labels
+}
+
+// readCSV returns the puzzles in column col of the CSV in r and, if solCol
+// is not empty, the expected solutions in that column. The first record
+// must hold the column headers.
+func readCSV(r io.Reader, col, solCol string) (puzzles, solutions []string) {
+	cr := csv.NewReader(r)
+	header, err := cr.Read()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "reading puzzles:", err)
+		os.Exit(1)
+	}
+	puzzleIdx, solIdx := slices.Index(header, col), slices.Index(header, solCol)
+	if puzzleIdx < 0 || (solCol != "" && solIdx < 0) {
+		fmt.Fprintf(os.Stderr, "reading puzzles: CSV header %q lacks a requested column\n", header)
+		os.Exit(1)
+	}
+
+	for {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "reading puzzles:", err)
+			os.Exit(1)
+		}
+		puzzles = append(puzzles, record[puzzleIdx])
+		if solCol != "" {
+			solutions = append(solutions, record[solIdx])
+		}
+	}
+	return puzzles, solutions
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+// TestMain runs main instead of the tests when the binary is started by
+// runMain, so the tests can drive the command through its flags.
+func TestMain(m *testing.M) {
+	if os.Getenv("SUDOKU_RUN_MAIN") == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runMain runs the command with args and input on stdin, returning what it
+// wrote to stdout and its exit code.
+func runMain(t *testing.T, input string, args ...string) (string, int) {
+	t.Helper()
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), "SUDOKU_RUN_MAIN=1")
+	cmd.Stdin = strings.NewReader(input)
+	var stdout bytes.Buffer
+	cmd.Stdout = &stdout
+	err := cmd.Run()
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return stdout.String(), exit.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	return stdout.String(), 0
+}
+
+func TestCount(t *testing.T) {
+	puzzles := strings.Join([]string{
+		"53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79",
+		strings.Repeat(".", 81),
+		// Row 1 needs a 9 that column 9 already holds.
+		"12345678.........9...............................................................",
+	}, "\n") + "\n"
+
+	out, code := runMain(t, puzzles, "-count")
+	if want := "unique\nmultiple\nnone\n"; code != 0 || out != want {
+		t.Errorf("-count wrote %q with exit code %d, want %q", out, code, want)
+	}
+	out, code = runMain(t, puzzles, "-mode", "count", "-limit", "3")
+	if want := "1\n3\n0\n"; code != 0 || out != want {
+		t.Errorf("-mode count wrote %q with exit code %d, want %q", out, code, want)
+	}
+	conflicts := [][]string{
+		{"-count", "-mode", "all"},
+		{"-count", "-limit", "5"},
+		{"-count", "-workers", "2"},
+		{"-mode", "all", "-timeout", "1s"},
+		{"-mode", "count", "-verify"},
+	}
+	for _, args := range conflicts {
+		if _, code := runMain(t, puzzles, args...); code != 2 {
+			t.Errorf("%v exited with %d, want 2", args, code)
+		}
+	}
+}
+
+func TestTimeout(t *testing.T) {
+	easy := "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79\n"
+	if out, code := runMain(t, easy, "-timeout", "1ns"); code != 0 || out != "timeout\n" {
+		t.Errorf("-timeout 1ns wrote %q with exit code %d, want \"timeout\\n\"", out, code)
+	}
+}
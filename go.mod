@@ -1 +1,3 @@
 module go-sudoku-solver
+
+go 1.21
@@ -0,0 +1,80 @@
+// Package cliio holds the input and output handling shared by the
+// commands, so that they treat -in and -out files the same way.
+package cliio
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// Open returns stdin when path is empty and the named file otherwise.
+// Compressed datasets ending in .gz are read as they decompress.
+func Open(path string) (io.ReadCloser, error) {
+	if path == "" {
+		return io.NopCloser(os.Stdin), nil
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return f, nil
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return gzipFile{gz, f}, nil
+}
+
+// gzipFile closes both the decompressor and the file beneath it.
+type gzipFile struct {
+	*gzip.Reader
+	f *os.File
+}
+
+func (g gzipFile) Close() error {
+	err := g.Reader.Close()
+	if ferr := g.f.Close(); err == nil {
+		err = ferr
+	}
+	return err
+}
+
+// Create returns stdout when path is empty and a new file at path otherwise.
+func Create(path string) (*os.File, error) {
+	if path == "" {
+		return os.Stdout, nil
+	}
+	return os.Create(path)
+}
+
+// Close flushes w and closes f unless it is stdout, returning the first
+// error. bufio.Writer keeps the first write error, so this also reports
+// failed writes.
+func Close(w *bufio.Writer, f *os.File) error {
+	err := w.Flush()
+	if f != os.Stdout {
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// Fail reports a failed write and exits, removing the output file so that a
+// truncated one is not mistaken for a complete run. Only regular files are
+// removed; devices and pipes are left alone.
+func Fail(path string, err error) {
+	fmt.Fprintln(os.Stderr, "writing solutions:", err)
+	if fi, serr := os.Stat(path); path != "" && serr == nil && fi.Mode().IsRegular() {
+		os.Remove(path)
+		fmt.Fprintf(os.Stderr, "removed incomplete %s\n", path)
+	}
+	os.Exit(1)
+}
@@ -0,0 +1,30 @@
+package sudoku
+
+import "fmt"
+
+// FromArray builds a classic 9x9 puzzle from a grid of digits indexed by
+// row then column, where 0 marks an empty cell. It returns an error
+// wrapping ErrInvalid if a value is outside 0 to 9.
+func FromArray(grid [SIZE][SIZE]int) (*Puzzle, error) {
+	rows := make([][]int, SIZE)
+	for i := range grid {
+		rows[i] = grid[i][:]
+	}
+	return fromGrid(rows)
+}
+
+// ToArray returns the current contents of a classic 9x9 board as digits
+// indexed by row then column, with 0 for empty cells. Boards of any other
+// size do not fit and give an error instead; MarshalJSON covers every size.
+func (p *Puzzle) ToArray() ([SIZE][SIZE]int, error) {
+	var grid [SIZE][SIZE]int
+	if p.size != SIZE {
+		return grid, fmt.Errorf("sudoku: %dx%d board does not fit in a %dx%d array", p.size, p.size, SIZE, SIZE)
+	}
+	for i := 0; i < SIZE; i++ {
+		for j := 0; j < SIZE; j++ {
+			grid[i][j] = int(p.cells[i*SIZE+j])
+		}
+	}
+	return grid, nil
+}
@@ -0,0 +1,380 @@
+package sudoku
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"math/rand"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// inFlightPerWorker bounds how many puzzles per worker may be started ahead
+// of the oldest unfinished one when results are streamed in input order.
+const inFlightPerWorker = 64
+
+// job is a puzzle handed to a solveStream worker, with its position in the
+// input.
+type job struct {
+	index  int
+	puzzle string
+}
+
+// result is what a solveStream worker reports for one job. err says why a
+// puzzle was not solved, as for SolvePuzzlesErr. stats is the work the
+// search did, with Elapsed covering parsing as well as solving.
+type result struct {
+	index    int
+	solution string
+	solved   bool
+	err      error
+	stats    Stats
+}
+
+// SolvePuzzles solves the puzzles concurrently using one worker per CPU.
+// Solutions are returned in input order, along with a parallel slice that is
+// false for each puzzle that was invalid or had no solution; the solution of
+// such a puzzle is the empty string.
+func SolvePuzzles(puzzles []string) ([]string, []bool) {
+	return SolvePuzzlesN(puzzles, 0)
+}
+
+// SolvePuzzlesN is like SolvePuzzles but runs at most workers goroutines.
+// A workers value <= 0 means one per CPU.
+func SolvePuzzlesN(puzzles []string, workers int) ([]string, []bool) {
+	solutions := make([]string, len(puzzles))
+	solved := make([]bool, len(puzzles))
+	SolvePuzzlesFunc(puzzles, workers, func(index int, solution string, ok bool) error {
+		solutions[index] = solution
+		solved[index] = ok
+		return nil
+	})
+	return solutions, solved
+}
+
+// SolvePuzzlesTimed is like SolvePuzzlesN but also returns how long each
+// puzzle took to parse and solve, so the spread of solve times can be
+// examined rather than just the average. Times are measured inside the
+// workers and exclude waiting for one.
+func SolvePuzzlesTimed(puzzles []string, workers int) ([]string, []bool, []time.Duration) {
+	solutions := make([]string, len(puzzles))
+	solved := make([]bool, len(puzzles))
+	elapsed := make([]time.Duration, len(puzzles))
+	solveStream(fromSlice(puzzles), streamConfig{workers: workers}, func(r result) error {
+		solutions[r.index], solved[r.index], elapsed[r.index] = r.solution, r.solved, r.stats.Elapsed
+		return nil
+	})
+	return solutions, solved, elapsed
+}
+
+// SolvePuzzlesTo solves the puzzles concurrently and writes one line per
+// puzzle to w, in input order, as soon as each result is ready. Puzzles that
+// are invalid or have no solution are written as "No solution found".
+func SolvePuzzlesTo(w io.Writer, puzzles []string, workers int) error {
+	bw := bufio.NewWriter(w)
+	err := SolvePuzzlesFunc(puzzles, workers, func(index int, solution string, ok bool) error {
+		if !ok {
+			solution = "No solution found"
+		}
+		_, err := bw.WriteString(solution + "\n")
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// SolvePuzzlesFunc solves the puzzles concurrently with at most workers
+// goroutines (one per CPU if workers <= 0) and calls emit for each puzzle in
+// input order as soon as it and every earlier puzzle are done. Only a bounded
+// number of puzzles are started ahead of the oldest unfinished one, so memory
+// does not grow with the input. emit is never called concurrently; if it
+// returns an error, no further puzzles are started and that error is
+// returned.
+func SolvePuzzlesFunc(puzzles []string, workers int, emit func(index int, solution string, solved bool) error) error {
+	return solveStream(fromSlice(puzzles), streamConfig{workers: workers, ordered: true}, emitFunc(emit))
+}
+
+// SolvePuzzlesProgress is like SolvePuzzlesFunc but also calls progress
+// each time another every puzzles have been solved, with the number solved
+// so far. Puzzles are counted as they finish rather than in input order, so
+// progress keeps moving while one slow puzzle holds back emit. progress is
+// called from the worker goroutines and must be safe for concurrent use.
+func SolvePuzzlesProgress(puzzles []string, workers, every int, progress func(solved int), emit func(index int, solution string, solved bool) error) error {
+	return SolvePuzzlesWith(puzzles, BatchOptions{Workers: workers, Every: every, Progress: progress}, emit)
+}
+
+// BatchOptions controls SolvePuzzlesWith.
+type BatchOptions struct {
+	// Workers is the number of solver goroutines, one per CPU if <= 0.
+	Workers int
+
+	// Progress, if set, is called as for SolvePuzzlesProgress each time
+	// another Every puzzles have been solved.
+	Every    int
+	Progress func(solved int)
+
+	// Setup, if set, is called on every board after it is parsed and
+	// before it is solved, to apply variant constraints such as
+	// SetVariant that the one-line form cannot carry. A board it returns
+	// an error for is reported as unsolved. It is called from the worker
+	// goroutines.
+	Setup func(p *Puzzle) error
+
+	// MaxNodes, if positive, gives up on any puzzle whose search passes
+	// that many nodes, as SetNodeLimit does; it is reported as unsolved.
+	MaxNodes int
+
+	// Seed, if not zero, makes every puzzle try its candidates in a random
+	// order, as SetRandom does, drawn from a source seeded with Seed plus
+	// the puzzle's index. Runs with the same seed search identically
+	// whatever the number of workers, which keeps benchmarks of the
+	// randomized search repeatable.
+	Seed int64
+
+	// Timeout, if positive, gives up on any puzzle not solved within that
+	// long of a worker starting on it, so one hard puzzle cannot stall the
+	// batch. It is reported as unsolved.
+	Timeout time.Duration
+
+	// Stats, if set, is called just before emit for each puzzle with the
+	// work its solve did, as SolveStats reports it. Elapsed is measured in
+	// the worker and covers parsing as well as solving.
+	Stats func(index int, stats Stats)
+}
+
+// SolvePuzzlesWith is the batch solver behind SolvePuzzlesFunc and
+// SolvePuzzlesProgress with every option exposed. emit is called in input
+// order as for SolvePuzzlesFunc.
+func SolvePuzzlesWith(puzzles []string, opts BatchOptions, emit func(index int, solution string, solved bool) error) error {
+	return SolvePuzzlesErr(puzzles, opts, func(index int, solution string, err error) error {
+		return emit(index, solution, err == nil)
+	})
+}
+
+// SolvePuzzlesErr is like SolvePuzzlesWith but tells emit why a puzzle was
+// not solved. err is nil for a solved puzzle; otherwise it wraps ErrInvalid,
+// or is ErrNoSolution, ErrNodeLimit when BatchOptions.MaxNodes was passed,
+// or context.DeadlineExceeded when BatchOptions.Timeout ran out.
+func SolvePuzzlesErr(puzzles []string, opts BatchOptions, emit func(index int, solution string, err error) error) error {
+	cfg := streamConfig{workers: opts.Workers, ordered: true, setup: opts.Setup, seed: opts.Seed, maxNodes: opts.MaxNodes, timeout: opts.Timeout}
+	if opts.Progress != nil && opts.Every > 0 {
+		var solved atomic.Int64
+		cfg.onSolved = func() {
+			if n := solved.Add(1); n%int64(opts.Every) == 0 {
+				opts.Progress(int(n))
+			}
+		}
+	}
+	return solveStream(fromSlice(puzzles), cfg, func(r result) error {
+		if opts.Stats != nil {
+			opts.Stats(r.index, r.stats)
+		}
+		return emit(r.index, r.solution, r.err)
+	})
+}
+
+// emitFunc adapts an exported per-puzzle callback to solveStream.
+func emitFunc(emit func(index int, solution string, solved bool) error) func(result) error {
+	return func(r result) error {
+		return emit(r.index, r.solution, r.solved)
+	}
+}
+
+// fromSlice returns a puzzle source for solveStream that yields puzzles in
+// order.
+func fromSlice(puzzles []string) func() (string, bool) {
+	i := 0
+	return func() (string, bool) {
+		if i == len(puzzles) {
+			return "", false
+		}
+		i++
+		return puzzles[i-1], true
+	}
+}
+
+// SolveReader reads puzzles from r in any format accepted by Scanner, solves
+// them concurrently with one worker per CPU and writes one line per puzzle
+// to w in input order, like SolvePuzzlesTo. Input is read only as fast as
+// solutions are written, so arbitrarily long streams run in bounded memory.
+// Unrecognised input is skipped.
+func SolveReader(r io.Reader, w io.Writer) error {
+	return SolveStream(r, w, 0, true)
+}
+
+// SolveStream is like SolveReader but runs at most workers goroutines (one
+// per CPU if workers <= 0) and lets the caller choose the output order.
+// With ordered set, lines come out in input order: results that finish
+// early are held until every earlier puzzle is written, which costs up to
+// workers*64 buffered solutions while a slow puzzle is outstanding, and
+// stops new puzzles being started until it completes. Without it, each
+// line is written as soon as its puzzle is solved, nothing is buffered and
+// no worker waits, but the order of lines follows completion rather than
+// the input.
+func SolveStream(r io.Reader, w io.Writer, workers int, ordered bool) error {
+	scanner := NewScanner(r)
+	next := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		return scanner.Text(), true
+	}
+	bw := bufio.NewWriter(w)
+	err := solveStream(next, streamConfig{workers: workers, ordered: ordered}, func(r result) error {
+		solution := r.solution
+		if !r.solved {
+			solution = "No solution found"
+		}
+		_, err := bw.WriteString(solution + "\n")
+		return err
+	})
+	if err == nil {
+		err = scanner.Err()
+	}
+	if err != nil {
+		return err
+	}
+	return bw.Flush()
+}
+
+// streamConfig holds the settings of one solveStream run.
+type streamConfig struct {
+	workers int
+	// ordered makes emit see results in input order rather than in the
+	// order they finish.
+	ordered bool
+	// onSolved, if not nil, is called by a worker after each puzzle.
+	onSolved func()
+	// setup, seed, maxNodes and timeout, if not zero, apply the
+	// BatchOptions fields of the same names.
+	setup    func(*Puzzle) error
+	seed     int64
+	maxNodes int
+	timeout  time.Duration
+}
+
+// newWorker returns the function a solveStream worker runs on each job,
+// holding the worker's own Solver.
+func newWorker(cfg streamConfig) func(job) result {
+	solver := Solver{setup: cfg.setup}
+	solver.scratch.maxNodes = cfg.maxNodes
+	if cfg.seed != 0 {
+		solver.scratch.rng = rand.New(rand.NewSource(cfg.seed))
+	}
+	return func(j job) result {
+		if cfg.seed != 0 {
+			solver.scratch.rng.Seed(cfg.seed + int64(j.index))
+		}
+		ctx := context.Background()
+		if cfg.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cfg.timeout)
+			defer cancel()
+		}
+		start := time.Now()
+		solution, stats, err := solver.solveString(ctx, j.puzzle)
+		stats.Elapsed = time.Since(start)
+		if cfg.onSolved != nil {
+			cfg.onSolved()
+		}
+		return result{index: j.index, solution: solution, solved: err == nil, err: err, stats: stats}
+	}
+}
+
+// solveStream is the engine behind SolvePuzzlesWith and SolveStream. next
+// is called from a single goroutine and returns puzzles until it reports
+// false.
+func solveStream(next func() (string, bool), cfg streamConfig, emit func(result) error) error {
+	numWorkers := cfg.workers
+	if numWorkers <= 0 {
+		numWorkers = runtime.NumCPU()
+	}
+	if numWorkers == 1 {
+		// With nothing to run in parallel, skip the goroutines and
+		// channels and solve in the caller.
+		work := newWorker(cfg)
+		for i := 0; ; i++ {
+			puzzle, ok := next()
+			if !ok {
+				return nil
+			}
+			if err := emit(work(job{i, puzzle})); err != nil {
+				return err
+			}
+		}
+	}
+
+	jobs := make(chan job)
+	results := make(chan result, numWorkers)
+	tokens := make(chan struct{}, numWorkers*inFlightPerWorker)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(jobs)
+		for i := 0; ; i++ {
+			select {
+			case tokens <- struct{}{}:
+			case <-done:
+				return
+			}
+			puzzle, ok := next()
+			if !ok {
+				return
+			}
+			select {
+			case jobs <- job{i, puzzle}:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for i := 0; i < numWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			work := newWorker(cfg)
+			for j := range jobs {
+				results <- work(j)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	var err error
+	pending := make(map[int]result)
+	want := 0
+	for r := range results {
+		if err != nil {
+			continue
+		}
+		if !cfg.ordered {
+			if err = emit(r); err != nil {
+				close(done)
+				continue
+			}
+			<-tokens
+			continue
+		}
+		pending[r.index] = r
+		for r, ok := pending[want]; ok; r, ok = pending[want] {
+			delete(pending, want)
+			if err = emit(r); err != nil {
+				close(done)
+				break
+			}
+			want++
+			<-tokens
+		}
+	}
+	return err
+}
@@ -0,0 +1,229 @@
+package sudoku
+
+import (
+	"math/bits"
+	"testing"
+)
+
+// benchPuzzles is a small corpus of representative boards, from an easy
+// newspaper puzzle to some of the hardest known for backtracking solvers.
+var benchPuzzles = []struct {
+	name   string
+	puzzle string
+}{
+	{"easy", "53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79"},
+	{"17-clue", "..............1..234.....5..6..3............1..7..2..8....5.46........3.8.9......"},
+	{"inkala", "8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4.."},
+	{"ai-escargot", "1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3.."},
+}
+
+func BenchmarkSolve(b *testing.B) {
+	for _, bp := range benchPuzzles {
+		b.Run(bp.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				p, err := ParsePuzzle(bp.puzzle)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if !p.Solve() {
+					b.Fatalf("%s: no solution", bp.name)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSolveDLX(b *testing.B) {
+	for _, bp := range benchPuzzles {
+		b.Run(bp.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				p, err := ParsePuzzle(bp.puzzle)
+				if err != nil {
+					b.Fatal(err)
+				}
+				if !p.SolveDLX() {
+					b.Fatalf("%s: no solution", bp.name)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkSolveHiddenPairs(b *testing.B) {
+	for _, bp := range benchPuzzles {
+		b.Run(bp.name, func(b *testing.B) {
+			nodes := 0
+			for i := 0; i < b.N; i++ {
+				p, err := ParsePuzzle(bp.puzzle)
+				if err != nil {
+					b.Fatal(err)
+				}
+				p.SetHiddenPairs(true)
+				stats, ok := p.SolveStats()
+				if !ok {
+					b.Fatalf("%s: no solution", bp.name)
+				}
+				nodes += stats.Nodes
+			}
+			b.ReportMetric(float64(nodes)/float64(b.N), "nodes/op")
+		})
+	}
+}
+
+func BenchmarkSolveBoxLineReduction(b *testing.B) {
+	for _, bp := range benchPuzzles {
+		b.Run(bp.name, func(b *testing.B) {
+			nodes := 0
+			for i := 0; i < b.N; i++ {
+				p, err := ParsePuzzle(bp.puzzle)
+				if err != nil {
+					b.Fatal(err)
+				}
+				p.SetBoxLineReduction(true)
+				stats, ok := p.SolveStats()
+				if !ok {
+					b.Fatalf("%s: no solution", bp.name)
+				}
+				nodes += stats.Nodes
+			}
+			b.ReportMetric(float64(nodes)/float64(b.N), "nodes/op")
+		})
+	}
+}
+
+func BenchmarkSolveFish(b *testing.B) {
+	for _, bp := range benchPuzzles {
+		b.Run(bp.name, func(b *testing.B) {
+			nodes := 0
+			for i := 0; i < b.N; i++ {
+				p, err := ParsePuzzle(bp.puzzle)
+				if err != nil {
+					b.Fatal(err)
+				}
+				p.SetFish(3)
+				stats, ok := p.SolveStats()
+				if !ok {
+					b.Fatalf("%s: no solution", bp.name)
+				}
+				nodes += stats.Nodes
+			}
+			b.ReportMetric(float64(nodes)/float64(b.N), "nodes/op")
+		})
+	}
+}
+
+func BenchmarkSolveInto(b *testing.B) {
+	for _, bp := range benchPuzzles {
+		b.Run(bp.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var s Solver
+			var p Puzzle
+			for i := 0; i < b.N; i++ {
+				if !s.SolveInto(bp.puzzle, &p) {
+					b.Fatalf("%s: no solution", bp.name)
+				}
+			}
+		})
+	}
+}
+
+func BenchmarkParse(b *testing.B) {
+	for _, bp := range benchPuzzles {
+		b.Run(bp.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := ParsePuzzle(bp.puzzle); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkLoad is BenchmarkParse reusing one Puzzle, as a batch would.
+func BenchmarkLoad(b *testing.B) {
+	for _, bp := range benchPuzzles {
+		b.Run(bp.name, func(b *testing.B) {
+			b.ReportAllocs()
+			var p Puzzle
+			for i := 0; i < b.N; i++ {
+				if err := p.Load(bp.puzzle); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+// BenchmarkFindBestCell measures the MRV scan on a hard board once
+// propagation has stalled, so no cell has a single candidate left.
+func BenchmarkFindBestCell(b *testing.B) {
+	p, err := ParsePuzzle(benchPuzzles[3].puzzle)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !p.propagate() {
+		b.Fatal("contradiction")
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, _, ok := p.findBestCell(); !ok {
+			b.Fatal("board is full")
+		}
+	}
+}
+
+// BenchmarkCandidateBits compares math/bits against the 16-bit popcount and
+// first-digit tables the solver used before, over the candidate masks of a
+// hard board once propagation has stalled.
+func BenchmarkCandidateBits(b *testing.B) {
+	p, err := ParsePuzzle(benchPuzzles[3].puzzle)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if !p.propagate() {
+		b.Fatal("contradiction")
+	}
+	var masks []uint32
+	for idx, val := range p.cells {
+		if val == 0 {
+			masks = append(masks, p.getPossibilities(idx/p.size, idx%p.size))
+		}
+	}
+
+	var bitCount [1 << 16]uint8
+	var firstDigit [1 << 16]int8
+	for i := range bitCount {
+		bitCount[i] = uint8(bits.OnesCount16(uint16(i)))
+		firstDigit[i] = int8(bits.TrailingZeros16(uint16(i)))
+	}
+
+	b.Run("table", func(b *testing.B) {
+		sum := 0
+		for i := 0; i < b.N; i++ {
+			for _, m := range masks {
+				sum += int(bitCount[m&0xFFFF]) + int(bitCount[m>>16])
+				if lo := m & 0xFFFF; lo != 0 {
+					sum += int(firstDigit[lo])
+				} else {
+					sum += 16 + int(firstDigit[m>>16])
+				}
+			}
+		}
+		sink = sum
+	})
+	b.Run("bits", func(b *testing.B) {
+		sum := 0
+		for i := 0; i < b.N; i++ {
+			for _, m := range masks {
+				sum += bits.OnesCount32(m) + bits.TrailingZeros32(m)
+			}
+		}
+		sink = sum
+	})
+}
+
+// sink keeps the compiler from discarding benchmark results.
+var sink int
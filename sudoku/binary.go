@@ -0,0 +1,75 @@
+package sudoku
+
+import "fmt"
+
+// nibbleMax is the largest board size stored at four bits per cell by
+// MarshalBinary; larger boards use a byte per cell.
+const nibbleMax = 15
+
+// binaryLen returns the length of MarshalBinary's output for a board of
+// the given size.
+func binaryLen(size int) int {
+	if size <= nibbleMax {
+		return (size*size + 1) / 2
+	}
+	return size * size
+}
+
+// MarshalBinary encodes the board compactly: the cells in row order, two to
+// a byte with the first in the high nibble and 0 for empty, so a classic
+// puzzle takes 41 bytes. 16x16 and 25x25 boards, whose digits do not fit in
+// a nibble, use one byte per cell. The size is implied by the length.
+// Variant constraints are not stored.
+func (p *Puzzle) MarshalBinary() ([]byte, error) {
+	data := make([]byte, binaryLen(p.size))
+	for idx, val := range p.cells {
+		switch {
+		case p.size > nibbleMax:
+			data[idx] = val
+		case idx%2 == 0:
+			data[idx/2] |= val << 4
+		default:
+			data[idx/2] |= val
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary decodes a board written by MarshalBinary, replacing the
+// contents of p and rebuilding its masks.
+func (p *Puzzle) UnmarshalBinary(data []byte) error {
+	var l *layout
+	for _, cand := range layouts {
+		if binaryLen(cand.size) == len(data) {
+			l = cand
+		}
+	}
+	if l == nil {
+		return fmt.Errorf("%w: %d bytes is not the binary length of a supported board", ErrInvalid, len(data))
+	}
+	if l.size <= nibbleMax && l.numCells%2 == 1 && data[len(data)-1]&0xf != 0 {
+		return fmt.Errorf("%w: padding nibble is not zero", ErrInvalid)
+	}
+
+	q := newPuzzle(l)
+	for idx := 0; idx < l.numCells; idx++ {
+		var val byte
+		switch {
+		case l.size > nibbleMax:
+			val = data[idx]
+		case idx%2 == 0:
+			val = data[idx/2] >> 4
+		default:
+			val = data[idx/2] & 0xf
+		}
+		if int(val) > l.size {
+			return fmt.Errorf("%w: invalid digit %d at row %d, column %d", ErrInvalid, val, idx/l.size+1, idx%l.size+1)
+		}
+		if val != 0 {
+			q.setCell(idx/l.size, idx%l.size, val)
+		}
+	}
+	q.markGivens()
+	*p = *q
+	return nil
+}
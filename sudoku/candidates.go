@@ -0,0 +1,149 @@
+package sudoku
+
+import (
+	"math/bits"
+	"strings"
+)
+
+// Candidates returns, for every cell, the mask of digits that can still go
+// there: bit d-1 is set when digit d is possible. A filled cell has only the
+// bit of its own digit set.
+func (p *Puzzle) Candidates() [][]uint32 {
+	grid := make([][]uint32, p.size)
+	for i := range grid {
+		grid[i] = make([]uint32, p.size)
+		for j := range grid[i] {
+			if val := p.cells[i*p.size+j]; val != 0 {
+				grid[i][j] = 1 << (val - 1)
+			} else {
+				grid[i][j] = p.getPossibilities(i, j)
+			}
+		}
+	}
+	return grid
+}
+
+// Possibilities returns the digits that can legally go in the empty cell at
+// (row, col), in increasing order. A filled cell gives an empty slice.
+func (p *Puzzle) Possibilities(row, col int) ([]int, error) {
+	if err := p.checkCell(row, col); err != nil {
+		return nil, err
+	}
+	digits := []int{}
+	if p.cells[row*p.size+col] != 0 {
+		return digits, nil
+	}
+	for poss := p.getPossibilities(row, col); poss != 0; poss &= poss - 1 {
+		digits = append(digits, bits.TrailingZeros32(poss)+1)
+	}
+	return digits, nil
+}
+
+// CandidateHistogram counts the empty cells by how many candidates they
+// have: element n is the number of empty cells with n candidates, for n
+// from 0 to Size(). Any cell counted at 0 means the board has no solution.
+func (p *Puzzle) CandidateHistogram() []int {
+	hist := make([]int, p.size+1)
+	for _, cell := range p.EmptyCells() {
+		hist[bits.OnesCount32(p.getPossibilities(cell[0], cell[1]))]++
+	}
+	return hist
+}
+
+// PencilMarks renders the board like Pretty, but with every empty cell
+// listing its remaining candidates. Columns are padded to the widest cell.
+func (p *Puzzle) PencilMarks() string {
+	marks := make([]string, p.numCells)
+	width := 1
+	for i, row := range p.Candidates() {
+		for j, poss := range row {
+			var b strings.Builder
+			for d := 0; d < p.size; d++ {
+				if poss&(1<<d) != 0 {
+					b.WriteByte(DIGITS[d])
+				}
+			}
+			if b.Len() == 0 {
+				b.WriteByte(EMPTY)
+			}
+			marks[i*p.size+j] = b.String()
+			width = max(width, b.Len())
+		}
+	}
+
+	var b strings.Builder
+	boxWidth := p.boxCols*(width+1) + 1
+	border := "+" + strings.Repeat(strings.Repeat("-", boxWidth)+"+", p.size/p.boxCols) + "\n"
+	for i := 0; i < p.size; i++ {
+		if i%p.boxRows == 0 {
+			b.WriteString(border)
+		}
+		for j := 0; j < p.size; j++ {
+			if j%p.boxCols == 0 {
+				b.WriteString("| ")
+			}
+			mark := marks[i*p.size+j]
+			b.WriteString(mark)
+			b.WriteString(strings.Repeat(" ", width-len(mark)+1))
+		}
+		b.WriteString("|\n")
+	}
+	b.WriteString(border)
+	return b.String()
+}
+
+// PencilGrid renders the board with every cell drawn as a small block of
+// candidates laid out like a box, the classic pencil-mark picture: on a 9x9
+// board digit d of an empty cell sits at row (d-1)/3, column (d-1)%3 of
+// its 3x3 block, and digits that no longer fit are left blank. A filled
+// cell repeats its digit across the whole block so it cannot be mistaken
+// for a single candidate. The result ends with a newline.
+func (p *Puzzle) PencilGrid() string {
+	cellWidth := 2*p.boxCols - 1
+	segment := 2 + p.boxCols*cellWidth + 3*(p.boxCols-1)
+	border := "+" + strings.Repeat(strings.Repeat("-", segment)+"+", p.size/p.boxCols) + "\n"
+	blank := "|" + strings.Repeat(strings.Repeat(" ", segment)+"|", p.size/p.boxCols) + "\n"
+
+	var b strings.Builder
+	for i := 0; i < p.size; i++ {
+		if i%p.boxRows == 0 {
+			b.WriteString(border)
+		} else {
+			b.WriteString(blank)
+		}
+		for r := 0; r < p.boxRows; r++ {
+			for j := 0; j < p.size; j++ {
+				switch {
+				case j == 0:
+					b.WriteString("| ")
+				case j%p.boxCols == 0:
+					b.WriteString(" | ")
+				default:
+					b.WriteString("   ")
+				}
+				val := p.cells[i*p.size+j]
+				var poss uint32
+				if val == 0 {
+					poss = p.getPossibilities(i, j)
+				}
+				for c := 0; c < p.boxCols; c++ {
+					if c > 0 {
+						b.WriteByte(' ')
+					}
+					d := r*p.boxCols + c
+					switch {
+					case val != 0:
+						b.WriteByte(DIGITS[val-1])
+					case poss&(1<<d) != 0:
+						b.WriteByte(DIGITS[d])
+					default:
+						b.WriteByte(' ')
+					}
+				}
+			}
+			b.WriteString(" |\n")
+		}
+	}
+	b.WriteString(border)
+	return b.String()
+}
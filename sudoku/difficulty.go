@@ -0,0 +1,31 @@
+package sudoku
+
+import "context"
+
+// Difficulty solves a copy of the puzzle and rates it by the number of
+// search nodes visited. A puzzle that singles alone can solve takes one node
+// and rates "easy"; up to 10 nodes is "medium", up to 100 "hard", and
+// anything beyond is "evil". A puzzle that breaks the rules gets no rating
+// and an error wrapping ErrInvalid, and one with no solution gets
+// ErrNoSolution. The receiver is not modified.
+func (p *Puzzle) Difficulty() (nodes int, label string, err error) {
+	if err := p.Validate(); err != nil {
+		return 0, "", err
+	}
+	s := &search{ctx: context.Background()}
+	if !p.Clone().search(s) {
+		return s.nodes, "", ErrNoSolution
+	}
+
+	switch {
+	case s.nodes <= 1:
+		label = "easy"
+	case s.nodes <= 10:
+		label = "medium"
+	case s.nodes <= 100:
+		label = "hard"
+	default:
+		label = "evil"
+	}
+	return s.nodes, label, nil
+}
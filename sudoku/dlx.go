@@ -0,0 +1,159 @@
+package sudoku
+
+// dlx is Knuth's dancing links matrix for the exact cover form of a board:
+// one column per constraint (each cell filled, each digit once per row,
+// column and box) and one row per candidate placement. Node 0 is the root
+// header and nodes 1 to the number of columns are the column headers; the
+// links are indices into the slices.
+type dlx struct {
+	left, right, up, down []int
+	col                   []int // column header of each node
+	size                  []int // nodes in each column, by header
+	choice                []int // candidate of each node, idx*size + digit-1
+	solution              []int // candidates chosen so far
+}
+
+// newDLX returns a matrix with the given number of columns and room for
+// up to rows rows of four nodes.
+func newDLX(columns, rows int) *dlx {
+	nodes := columns + 1 + 4*rows
+	d := &dlx{
+		left:   make([]int, 0, nodes),
+		right:  make([]int, 0, nodes),
+		up:     make([]int, 0, nodes),
+		down:   make([]int, 0, nodes),
+		col:    make([]int, 0, nodes),
+		choice: make([]int, 0, nodes),
+		size:   make([]int, columns+1),
+	}
+	for i := 0; i <= columns; i++ {
+		d.left = append(d.left, i-1)
+		d.right = append(d.right, i+1)
+		d.up = append(d.up, i)
+		d.down = append(d.down, i)
+		d.col = append(d.col, i)
+		d.choice = append(d.choice, -1)
+	}
+	d.left[0] = columns
+	d.right[columns] = 0
+	return d
+}
+
+// addRow appends a row covering the given columns for candidate choice.
+func (d *dlx) addRow(choice int, columns [4]int) {
+	first := len(d.col)
+	for i, c := range columns {
+		n := first + i
+		d.left = append(d.left, first+(i+3)%4)
+		d.right = append(d.right, first+(i+1)%4)
+		d.up = append(d.up, d.up[c])
+		d.down = append(d.down, c)
+		d.col = append(d.col, c)
+		d.choice = append(d.choice, choice)
+		d.down[d.up[c]] = n
+		d.up[c] = n
+		d.size[c]++
+	}
+}
+
+func (d *dlx) cover(c int) {
+	d.right[d.left[c]] = d.right[c]
+	d.left[d.right[c]] = d.left[c]
+	for i := d.down[c]; i != c; i = d.down[i] {
+		for j := d.right[i]; j != i; j = d.right[j] {
+			d.down[d.up[j]] = d.down[j]
+			d.up[d.down[j]] = d.up[j]
+			d.size[d.col[j]]--
+		}
+	}
+}
+
+func (d *dlx) uncover(c int) {
+	for i := d.up[c]; i != c; i = d.up[i] {
+		for j := d.left[i]; j != i; j = d.left[j] {
+			d.size[d.col[j]]++
+			d.down[d.up[j]] = j
+			d.up[d.down[j]] = j
+		}
+	}
+	d.right[d.left[c]] = c
+	d.left[d.right[c]] = c
+}
+
+// search looks for an exact cover, leaving its rows in d.solution.
+func (d *dlx) search() bool {
+	if d.right[0] == 0 {
+		return true
+	}
+	best := d.right[0]
+	for c := d.right[best]; c != 0; c = d.right[c] {
+		if d.size[c] < d.size[best] {
+			best = c
+		}
+	}
+	if d.size[best] == 0 {
+		return false
+	}
+
+	d.cover(best)
+	for r := d.down[best]; r != best; r = d.down[r] {
+		d.solution = append(d.solution, d.choice[r])
+		for j := d.right[r]; j != r; j = d.right[j] {
+			d.cover(d.col[j])
+		}
+		if d.search() {
+			return true
+		}
+		for j := d.left[r]; j != r; j = d.left[j] {
+			d.uncover(d.col[j])
+		}
+		d.solution = d.solution[:len(d.solution)-1]
+	}
+	d.uncover(best)
+	return false
+}
+
+// SolveDLX is an alternative to Solve that runs Algorithm X with dancing
+// links on the exact cover form of the board instead of the bitmask search.
+// It fills the puzzle in place and reports whether a solution was found.
+// Only rows, columns and boxes (or jigsaw regions) become exact cover
+// columns, so a board is handed to Solve instead if it has extra units from
+// SetDiagonal, SetWindoku or AddUnit, cages from SetCages, or candidate
+// limits from ParseMarked or Forbid.
+func (p *Puzzle) SolveDLX() bool {
+	if p.extras != nil || p.cageOf != nil || p.allowed != nil {
+		return p.solve()
+	}
+
+	n := p.size
+	d := newDLX(4*p.numCells, p.numCells*n)
+	for idx, val := range p.cells {
+		row, col := idx/n, idx%n
+		poss := p.getPossibilities(row, col)
+		if val != 0 {
+			poss = 1 << (val - 1)
+		}
+		for digit := 0; digit < n; digit++ {
+			if poss&(1<<digit) == 0 {
+				continue
+			}
+			d.addRow(idx*n+digit, [4]int{
+				1 + idx,
+				1 + p.numCells + row*n + digit,
+				1 + 2*p.numCells + col*n + digit,
+				1 + 3*p.numCells + p.boxOf[idx]*n + digit,
+			})
+		}
+	}
+	if !d.search() {
+		return false
+	}
+
+	for _, choice := range d.solution {
+		idx, val := choice/n, byte(choice%n+1)
+		if p.cells[idx] == 0 {
+			p.setCell(idx/n, idx%n, val)
+		}
+	}
+	return true
+}
@@ -0,0 +1,135 @@
+package sudoku
+
+import (
+	"fmt"
+	"slices"
+)
+
+// checkCell returns an error if (row, col) is not on the board.
+func (p *Puzzle) checkCell(row, col int) error {
+	if row < 0 || row >= p.size || col < 0 || col >= p.size {
+		return fmt.Errorf("sudoku: cell (%d, %d) is outside the %dx%d board", row, col, p.size, p.size)
+	}
+	return nil
+}
+
+// At returns the digit at (row, col), counted from 0, or 0 if the cell is
+// empty.
+func (p *Puzzle) At(row, col int) (int, error) {
+	if err := p.checkCell(row, col); err != nil {
+		return 0, err
+	}
+	return int(p.cells[row*p.size+col]), nil
+}
+
+// Set places val at (row, col), replacing any digit already there, or
+// empties the cell if val is 0. Givens cannot be changed, and a digit that
+// clashes with the rest of the board under the puzzle's constraints is
+// refused; both give an error wrapping ErrInvalid and leave the board
+// unchanged.
+func (p *Puzzle) Set(row, col, val int) error {
+	if err := p.checkCell(row, col); err != nil {
+		return err
+	}
+	if val < 0 || val > p.size {
+		return fmt.Errorf("sudoku: digit %d out of range for a %dx%d board", val, p.size, p.size)
+	}
+	if p.given(row*p.size + col) {
+		return fmt.Errorf("%w: cell (%d, %d) is a given", ErrInvalid, row, col)
+	}
+	old := p.cells[row*p.size+col]
+	if old != 0 {
+		p.clearCell(row, col, old)
+	}
+	if val == 0 {
+		return nil
+	}
+	if p.getPossibilities(row, col)&(1<<(val-1)) == 0 {
+		if old != 0 {
+			p.setCell(row, col, old)
+		}
+		return fmt.Errorf("%w: digit %c cannot go at (%d, %d)", ErrInvalid, DIGITS[val-1], row, col)
+	}
+	p.setCell(row, col, byte(val))
+	return nil
+}
+
+// edit is one Push: the cell changed and the digit it held before.
+type edit struct {
+	idx int
+	old byte
+}
+
+// Push is Set for interactive play: the change is also recorded so that
+// Undo can revert it. Moves Set refuses, including any on a given, are not
+// recorded.
+func (p *Puzzle) Push(row, col, val int) error {
+	if err := p.checkCell(row, col); err != nil {
+		return err
+	}
+	old := p.cells[row*p.size+col]
+	if err := p.Set(row, col, val); err != nil {
+		return err
+	}
+	p.history = append(p.history, edit{row*p.size + col, old})
+	return nil
+}
+
+// Undo reverts the most recent Push still in the history, restoring the
+// cell's previous contents, and reports false if there is nothing to undo.
+// Loading a new board clears the history.
+func (p *Puzzle) Undo() bool {
+	if len(p.history) == 0 {
+		return false
+	}
+	e := p.history[len(p.history)-1]
+	p.history = p.history[:len(p.history)-1]
+	row, col := e.idx/p.size, e.idx%p.size
+	if val := p.cells[e.idx]; val != 0 {
+		p.clearCell(row, col, val)
+	}
+	if e.old != 0 {
+		p.setCell(row, col, e.old)
+	}
+	return true
+}
+
+// Conflicts returns the cells, as (row, col) pairs counted from 0, that
+// already hold val in a row, column, box or extra unit shared with (row,
+// col), in board order. It is empty when val could go there, and for
+// coordinates or digits out of range.
+func (p *Puzzle) Conflicts(row, col, val int) [][2]int {
+	if p.checkCell(row, col) != nil || val < 1 || val > p.size {
+		return nil
+	}
+	idx := row*p.size + col
+	bit := uint32(1) << (val - 1)
+	used := p.rows[row] | p.cols[col] | p.boxes[p.boxOf[idx]]
+	if p.extraOf != nil {
+		for _, n := range p.extraOf[idx] {
+			used |= p.extras[n].used
+		}
+	}
+	if used&bit == 0 {
+		return nil
+	}
+
+	var cells [][2]int
+	for other, v := range p.cells {
+		if other == idx || int(v) != val {
+			continue
+		}
+		shared := other/p.size == row || other%p.size == col || p.boxOf[other] == p.boxOf[idx]
+		if !shared && p.extraOf != nil {
+			for _, n := range p.extraOf[idx] {
+				if slices.Contains(p.extras[n].cells, other) {
+					shared = true
+				}
+			}
+		}
+		if shared {
+			cells = append(cells, [2]int{other / p.size, other % p.size})
+		}
+	}
+	return cells
+}
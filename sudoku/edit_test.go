@@ -0,0 +1,83 @@
+package sudoku
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSetGiven(t *testing.T) {
+	// The easy puzzle starts with a given 5 and has a 4 at row 1, column 3
+	// in its solution.
+	p, err := ParsePuzzle(knownSolutions[0].puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, val := range []int{0, 1} {
+		if err := p.Set(0, 0, val); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Set(0, 0, %d) on a given = %v, want ErrInvalid", val, err)
+		}
+	}
+	if got, _ := p.At(0, 0); got != 5 {
+		t.Errorf("given at (0, 0) changed to %d", got)
+	}
+
+	if err := p.Set(0, 2, 4); err != nil {
+		t.Fatalf("Set(0, 2, 4) = %v", err)
+	}
+	if err := p.Set(0, 2, 0); err != nil {
+		t.Errorf("clearing a placed digit: %v", err)
+	}
+}
+
+func TestPushGiven(t *testing.T) {
+	p, err := ParsePuzzle(knownSolutions[0].puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Push(0, 0, 0); !errors.Is(err, ErrInvalid) {
+		t.Errorf("Push(0, 0, 0) on a given = %v, want ErrInvalid", err)
+	}
+	if p.Undo() {
+		t.Error("Undo reverted a refused Push")
+	}
+
+	if err := p.Push(0, 2, 4); err != nil {
+		t.Fatalf("Push(0, 2, 4) = %v", err)
+	}
+	if !p.Undo() {
+		t.Fatal("Undo found nothing to revert")
+	}
+	if got := p.ToString(); got != knownSolutions[0].puzzle {
+		t.Errorf("board after Undo = %s, want %s", got, knownSolutions[0].puzzle)
+	}
+}
+
+func TestConflicts(t *testing.T) {
+	solution := knownSolutions[0].solution
+	tests := []struct {
+		name     string
+		board    string
+		row, col int
+		val      int
+		want     [][2]int
+	}{
+		{"solved, own digit", solution, 0, 0, 5, nil},
+		{"solved, other digit", solution, 0, 0, 3, [][2]int{{0, 1}, {8, 0}}},
+		{"one empty cell, its digit", "." + solution[1:], 0, 0, 5, nil},
+		{"one empty cell, taken digit", "." + solution[1:], 0, 0, 3, [][2]int{{0, 1}, {8, 0}}},
+		// Row 1 needs a 9 that column 9 already holds.
+		{"contradiction", "12345678.........9...............................................................", 0, 8, 9, [][2]int{{1, 8}}},
+		{"out of range digit", solution, 0, 0, 10, nil},
+		{"outside", solution, SIZE, 0, 1, nil},
+	}
+	for _, tt := range tests {
+		p, err := ParsePuzzle(tt.board)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := p.Conflicts(tt.row, tt.col, tt.val); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Conflicts(%d, %d, %d) = %v, want %v", tt.name, tt.row, tt.col, tt.val, got, tt.want)
+		}
+	}
+}
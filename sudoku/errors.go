@@ -0,0 +1,57 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	// ErrInvalid is wrapped by every error reporting malformed or
+	// contradictory input.
+	ErrInvalid = errors.New("sudoku: invalid puzzle")
+
+	// ErrNoSolution is returned when a valid puzzle cannot be completed.
+	ErrNoSolution = errors.New("sudoku: no solution")
+
+	// ErrNodeLimit is returned when a search passes the limit set by
+	// SetNodeLimit before finding a solution.
+	ErrNodeLimit = errors.New("sudoku: search node limit reached")
+)
+
+// SolveE solves the puzzle in place like Solve, but reports failure as an
+// error: one wrapping ErrInvalid if the givens contradict each other,
+// ErrNodeLimit if the search gave up at the node limit, or ErrNoSolution if
+// it finds no completion.
+func (p *Puzzle) SolveE() error {
+	return p.solveE(p.newSearch(context.Background()))
+}
+
+// solveE is SolveE running the search s, whose error is returned if it was
+// interrupted.
+func (p *Puzzle) solveE(s *search) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	if p.run(s) {
+		return nil
+	}
+	if s.err != nil {
+		return s.err
+	}
+	return ErrNoSolution
+}
+
+// SolveString parses input as ParsePuzzle does, solves it and returns the
+// solution in the same one-line form. The error wraps ErrInvalid for
+// malformed or contradictory input and is ErrNoSolution if there is no
+// completion. Each call works on a fresh board.
+func SolveString(input string) (string, error) {
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		return "", err
+	}
+	if err := p.SolveE(); err != nil {
+		return "", err
+	}
+	return p.ToString(), nil
+}
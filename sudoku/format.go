@@ -0,0 +1,95 @@
+package sudoku
+
+import "strings"
+
+// Pretty renders the board as a grid with box borders, one row per line and
+// '.' for empty cells. The result ends with a newline.
+func (p *Puzzle) Pretty() string {
+	return p.pretty(false)
+}
+
+// PrettyGivens renders the board like Pretty but follows each given, as
+// reported by IsGiven, with a '*' instead of a space, so clues stand out
+// from digits filled in later:
+//
+//	| 5*3*4 | 6 7*8 | ...
+func (p *Puzzle) PrettyGivens() string {
+	return p.pretty(true)
+}
+
+func (p *Puzzle) pretty(markGivens bool) string {
+	var b strings.Builder
+	border := "+" + strings.Repeat(strings.Repeat("-", 2*p.boxCols+1)+"+", p.size/p.boxCols) + "\n"
+
+	for i := 0; i < p.size; i++ {
+		if i%p.boxRows == 0 {
+			b.WriteString(border)
+		}
+		for j := 0; j < p.size; j++ {
+			if j%p.boxCols == 0 {
+				b.WriteString("| ")
+			}
+			idx := i*p.size + j
+			if val := p.cells[idx]; val == 0 {
+				b.WriteByte(EMPTY)
+			} else {
+				b.WriteByte(DIGITS[val-1])
+			}
+			if markGivens && p.given(idx) {
+				b.WriteByte('*')
+			} else {
+				b.WriteByte(' ')
+			}
+		}
+		b.WriteString("|\n")
+	}
+	b.WriteString(border)
+	return b.String()
+}
+
+// String implements fmt.Stringer, rendering the board as Pretty does but
+// without the final newline, so fmt.Println(p) prints the grid. Use
+// ToString for the one-line form read back by ParsePuzzle.
+func (p *Puzzle) String() string {
+	return strings.TrimSuffix(p.Pretty(), "\n")
+}
+
+// FormatOptions controls how Format writes a board. The zero value gives
+// the same single line as ToString.
+type FormatOptions struct {
+	// Empty is written for an empty cell; 0 means '.'.
+	Empty rune
+	// Zeros writes '0' for empty cells, overriding Empty, for tools that
+	// do not accept '.'.
+	Zeros bool
+	// RowSeparator is written between consecutive rows, such as "\n" for
+	// one row per line.
+	RowSeparator string
+}
+
+// Format renders the board row by row according to opts.
+func (p *Puzzle) Format(opts FormatOptions) string {
+	empty := opts.Empty
+	switch {
+	case opts.Zeros:
+		empty = ZERO
+	case empty == 0:
+		empty = EMPTY
+	}
+
+	var b strings.Builder
+	b.Grow(p.numCells + (p.size-1)*len(opts.RowSeparator))
+	for i := 0; i < p.size; i++ {
+		if i > 0 {
+			b.WriteString(opts.RowSeparator)
+		}
+		for _, val := range p.cells[i*p.size : (i+1)*p.size] {
+			if val == 0 {
+				b.WriteRune(empty)
+			} else {
+				b.WriteByte(DIGITS[val-1])
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package sudoku
+
+import (
+	"context"
+	"math/rand"
+	"time"
+)
+
+// Symmetry is a clue pattern kept by GenerateSymmetric: whenever a cell is
+// cleared, so is its mirror image.
+type Symmetry int
+
+const (
+	NoSymmetry Symmetry = iota
+	// Rotational pairs each cell with the one a half turn away, the
+	// pattern used by most published puzzles.
+	Rotational
+	// Horizontal mirrors the top half of the board onto the bottom half.
+	Horizontal
+	// Vertical mirrors the left half of the board onto the right half.
+	Vertical
+)
+
+// partner returns the cell paired with idx on a board of the given size.
+func (s Symmetry) partner(idx, size int) int {
+	row, col := idx/size, idx%size
+	switch s {
+	case Rotational:
+		row, col = size-1-row, size-1-col
+	case Horizontal:
+		row = size - 1 - row
+	case Vertical:
+		col = size - 1 - col
+	}
+	return row*size + col
+}
+
+// Generate returns a random classic 9x9 puzzle with the given number of
+// clues. It fills a complete grid by solving an empty board with a random
+// candidate order, then clears cells one at a time. When clues is at least
+// 17, only removals that keep the solution unique are made, so the result may
+// keep more clues than requested if no further cell can be cleared. rng may
+// be nil, as for GenerateWith.
+func Generate(clues int, rng *rand.Rand) *Puzzle {
+	return GenerateSymmetric(clues, NoSymmetry, rng)
+}
+
+// GenerateSymmetric is like Generate but clears cells in pairs so the clues
+// form the pattern sym. A pair is skipped if clearing it would leave fewer
+// than clues givens or, when clues is at least 17, more than one solution.
+func GenerateSymmetric(clues int, sym Symmetry, rng *rand.Rand) *Puzzle {
+	return GenerateWith(GenerateOptions{Clues: clues, Symmetry: sym, MustBeUnique: clues >= MIN_CLUES}, rng)
+}
+
+// GenerateOptions controls GenerateWith.
+type GenerateOptions struct {
+	// Clues is the number of givens to aim for.
+	Clues int
+	// Symmetry is the pattern the clues keep.
+	Symmetry Symmetry
+	// MustBeUnique reverts every removal that would give the puzzle more
+	// than one solution, checked with CountSolutions(2). Without it cells
+	// are cleared freely, which yields ambiguous puzzles at low clue
+	// counts.
+	MustBeUnique bool
+}
+
+// GenerateWith is the generator behind Generate and GenerateSymmetric with
+// every option exposed. With MustBeUnique the result has exactly one
+// solution but may keep more clues than requested. A nil rng is replaced by
+// one seeded from the current time, so each call gives a different puzzle.
+func GenerateWith(opts GenerateOptions, rng *rand.Rand) *Puzzle {
+	if rng == nil {
+		rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	p := newPuzzle(layouts[SIZE])
+	p.search(&search{ctx: context.Background(), rng: rng})
+	p.commit()
+
+	clues, sym, unique := opts.Clues, opts.Symmetry, opts.MustBeUnique
+	for _, idx := range rng.Perm(p.numCells) {
+		if p.ClueCount() <= clues {
+			break
+		}
+		if p.cells[idx] == 0 {
+			continue
+		}
+		cleared := []int{idx}
+		if mate := sym.partner(idx, p.size); mate != idx {
+			cleared = append(cleared, mate)
+		}
+		if p.ClueCount()-len(cleared) < clues {
+			continue
+		}
+
+		vals := make([]byte, len(cleared))
+		for i, c := range cleared {
+			vals[i] = p.cells[c]
+			p.clearCell(c/p.size, c%p.size, vals[i])
+		}
+		if unique && p.CountSolutions(2) != 1 {
+			for i, c := range cleared {
+				p.setCell(c/p.size, c%p.size, vals[i])
+			}
+		}
+	}
+	p.markGivens()
+	return p
+}
@@ -0,0 +1,34 @@
+package sudoku
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestGenerateUnique(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for _, clues := range []int{20, 24, 30} {
+		for _, sym := range []Symmetry{NoSymmetry, Rotational} {
+			p := GenerateWith(GenerateOptions{Clues: clues, Symmetry: sym, MustBeUnique: true}, rng)
+			if n := p.CountSolutions(2); n != 1 {
+				t.Errorf("%d clues, symmetry %d: %s has %d solutions, want 1", clues, sym, p.ToString(), n)
+			}
+			if p.ClueCount() < clues {
+				t.Errorf("%d clues, symmetry %d: got only %d", clues, sym, p.ClueCount())
+			}
+			for idx, val := range p.cells {
+				row, col := idx/SIZE, idx%SIZE
+				if got, want := p.IsGiven(row, col), val != 0; got != want {
+					t.Errorf("%d clues, symmetry %d: IsGiven(%d, %d) = %v, want %v", clues, sym, row, col, got, want)
+				}
+			}
+		}
+	}
+}
+
+func TestGenerateNilRand(t *testing.T) {
+	p := Generate(30, nil)
+	if n := p.CountSolutions(2); n != 1 {
+		t.Errorf("Generate(30, nil) gave %s with %d solutions, want 1", p.ToString(), n)
+	}
+}
@@ -0,0 +1,86 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Hint finds the easiest next move a player can make by logic alone: a
+// naked single if there is one, otherwise a hidden single. It returns the
+// cell, counted from 0, and the digit, with a sentence explaining why the
+// digit must go there. When no single exists ok is false and reason says
+// whether the board is full, stuck on a contradiction or needs a guess;
+// Hint never guesses. The board is not modified.
+func (p *Puzzle) Hint() (row, col, digit int, reason string, ok bool) {
+	if p.emptyCell == 0 {
+		return 0, 0, 0, "the board is full", false
+	}
+
+	for _, cell := range p.EmptyCells() {
+		row, col := cell[0], cell[1]
+		poss := p.getPossibilities(row, col)
+		if poss == 0 {
+			return row, col, 0, fmt.Sprintf("no digit fits at row %d, column %d, so an earlier move was wrong", row+1, col+1), false
+		}
+		if poss&(poss-1) == 0 {
+			digit := bits.TrailingZeros32(poss) + 1
+			return row, col, digit, fmt.Sprintf("%s: %c is the only digit left for row %d, column %d", NakedSingle, DIGITS[digit-1], row+1, col+1), true
+		}
+	}
+
+	for n, unit := range p.unitList() {
+		// where[d] is the only cell that can take digit d+1, -2 while no
+		// cell can and -1 once two can or the digit is placed.
+		var where [MAX_SIZE]int
+		for d := range where {
+			where[d] = -2
+		}
+		for _, idx := range unit {
+			if val := p.cells[idx]; val != 0 {
+				where[val-1] = -1
+				continue
+			}
+			for poss := p.getPossibilities(idx/p.size, idx%p.size); poss != 0; poss &= poss - 1 {
+				d := bits.TrailingZeros32(poss)
+				if where[d] == -2 {
+					where[d] = idx
+				} else {
+					where[d] = -1
+				}
+			}
+		}
+		for d := 0; d < p.size; d++ {
+			if where[d] >= 0 {
+				row, col := where[d]/p.size, where[d]%p.size
+				return row, col, d + 1, fmt.Sprintf("%s: %c can only go at row %d, column %d in %s", HiddenSingle, DIGITS[d], row+1, col+1, p.unitName(n)), true
+			}
+		}
+	}
+	return 0, 0, 0, "no single is left; the next move needs a guess or a harder technique", false
+}
+
+// unitName describes unit n of unitList for messages.
+func (p *Puzzle) unitName(n int) string {
+	switch k := n / p.size; {
+	case k == rowKind:
+		return fmt.Sprintf("row %d", n%p.size+1)
+	case k == colKind:
+		return fmt.Sprintf("column %d", n%p.size+1)
+	case k == boxKind:
+		return fmt.Sprintf("box %d", n%p.size+1)
+	}
+	return p.extras[n-3*p.size].name
+}
+
+// NakedSingles returns the (row, col, digit) of every empty cell that has
+// exactly one candidate left, in row-major order. Unlike Hint it reports
+// them all at once; the board is not modified.
+func (p *Puzzle) NakedSingles() [][3]int {
+	var singles [][3]int
+	for _, cell := range p.EmptyCells() {
+		if poss := p.getPossibilities(cell[0], cell[1]); poss != 0 && poss&(poss-1) == 0 {
+			singles = append(singles, [3]int{cell[0], cell[1], bits.TrailingZeros32(poss) + 1})
+		}
+	}
+	return singles
+}
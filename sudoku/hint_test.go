@@ -0,0 +1,36 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestHint(t *testing.T) {
+	solution := knownSolutions[0].solution
+
+	p, err := ParsePuzzle(solution)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, _, reason, ok := p.Hint(); ok || reason != "the board is full" {
+		t.Errorf("Hint on a solved board = %q, %v; want the board is full", reason, ok)
+	}
+
+	// Row 1 needs a 9 that column 9 already holds.
+	p, err = ParsePuzzle("12345678.........9...............................................................")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if row, col, _, reason, ok := p.Hint(); ok || row != 0 || col != 8 || !strings.Contains(reason, "no digit fits") {
+		t.Errorf("Hint on a contradiction = (%d, %d) %q, %v; want (0, 8) with no digit fitting", row, col, reason, ok)
+	}
+
+	p, err = ParsePuzzle("." + solution[1:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	row, col, digit, reason, ok := p.Hint()
+	if !ok || row != 0 || col != 0 || digit != 5 || !strings.HasPrefix(reason, string(NakedSingle)) {
+		t.Errorf("Hint with one empty cell = (%d, %d) %d %q, %v; want (0, 0) 5 as a naked single", row, col, digit, reason, ok)
+	}
+}
@@ -0,0 +1,99 @@
+package sudoku
+
+import (
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+)
+
+const (
+	cellPixels  = 36 // side of one cell in RenderImage
+	glyphScale  = 4  // pixels per font dot
+	imageMargin = 2  // room for the outer border
+)
+
+// glyphs is a 5x7 bitmap font for the characters of DIGITS, one string of
+// five dots per row.
+var glyphs = [len(DIGITS)][7]string{
+	{"..#..", ".##..", "..#..", "..#..", "..#..", "..#..", ".###."}, // 1
+	{".###.", "#...#", "....#", "...#.", "..#..", ".#...", "#####"}, // 2
+	{".###.", "#...#", "....#", "..##.", "....#", "#...#", ".###."}, // 3
+	{"...#.", "..##.", ".#.#.", "#..#.", "#####", "...#.", "...#."}, // 4
+	{"#####", "#....", "####.", "....#", "....#", "#...#", ".###."}, // 5
+	{".###.", "#....", "#....", "####.", "#...#", "#...#", ".###."}, // 6
+	{"#####", "....#", "...#.", "..#..", ".#...", ".#...", ".#..."}, // 7
+	{".###.", "#...#", "#...#", ".###.", "#...#", "#...#", ".###."}, // 8
+	{".###.", "#...#", "#...#", ".####", "....#", "....#", ".###."}, // 9
+	{".###.", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"}, // A
+	{"####.", "#...#", "#...#", "####.", "#...#", "#...#", "####."}, // B
+	{".###.", "#...#", "#....", "#....", "#....", "#...#", ".###."}, // C
+	{"####.", "#...#", "#...#", "#...#", "#...#", "#...#", "####."}, // D
+	{"#####", "#....", "#....", "####.", "#....", "#....", "#####"}, // E
+	{"#####", "#....", "#....", "####.", "#....", "#....", "#...."}, // F
+	{".###.", "#...#", "#....", "#.###", "#...#", "#...#", ".####"}, // G
+	{"#...#", "#...#", "#...#", "#####", "#...#", "#...#", "#...#"}, // H
+	{".###.", "..#..", "..#..", "..#..", "..#..", "..#..", ".###."}, // I
+	{"..###", "...#.", "...#.", "...#.", "...#.", "#..#.", ".##.."}, // J
+	{"#...#", "#..#.", "#.#..", "##...", "#.#..", "#..#.", "#...#"}, // K
+	{"#....", "#....", "#....", "#....", "#....", "#....", "#####"}, // L
+	{"#...#", "##.##", "#.#.#", "#.#.#", "#...#", "#...#", "#...#"}, // M
+	{"#...#", "##..#", "#.#.#", "#..##", "#...#", "#...#", "#...#"}, // N
+	{".###.", "#...#", "#...#", "#...#", "#...#", "#...#", ".###."}, // O
+	{"####.", "#...#", "#...#", "####.", "#....", "#....", "#...."}, // P
+}
+
+// RenderImage draws the board as a black-on-white grid with bold borders
+// around the boxes and each filled cell's digit centred in it. Empty cells
+// are left blank.
+func (p *Puzzle) RenderImage() image.Image {
+	side := p.size*cellPixels + 2*imageMargin
+	img := image.NewPaletted(image.Rect(0, 0, side, side), color.Palette{color.White, color.Black})
+
+	fill := func(x0, y0, x1, y1 int) {
+		for y := y0; y < y1; y++ {
+			for x := x0; x < x1; x++ {
+				img.SetColorIndex(x, y, 1)
+			}
+		}
+	}
+
+	for i := 0; i <= p.size; i++ {
+		at := imageMargin + i*cellPixels
+		// Vertical lines after every boxCols columns and horizontal
+		// ones after every boxRows rows are drawn bold.
+		width := 1
+		if i%p.boxCols == 0 {
+			width = 2
+		}
+		fill(at-width, 0, at+width, side)
+		width = 1
+		if i%p.boxRows == 0 {
+			width = 2
+		}
+		fill(0, at-width, side, at+width)
+	}
+
+	glyphW, glyphH := 5*glyphScale, 7*glyphScale
+	for idx, val := range p.cells {
+		if val == 0 {
+			continue
+		}
+		x0 := imageMargin + idx%p.size*cellPixels + (cellPixels-glyphW)/2
+		y0 := imageMargin + idx/p.size*cellPixels + (cellPixels-glyphH)/2
+		for r, dots := range glyphs[val-1] {
+			for c := 0; c < len(dots); c++ {
+				if dots[c] == '#' {
+					x, y := x0+c*glyphScale, y0+r*glyphScale
+					fill(x, y, x+glyphScale, y+glyphScale)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes the image from RenderImage to w as a PNG.
+func (p *Puzzle) WritePNG(w io.Writer) error {
+	return png.Encode(w, p.RenderImage())
+}
@@ -0,0 +1,62 @@
+package sudoku
+
+import "context"
+
+// frame is one level of the explicit stack used by SolveIterative.
+type frame struct {
+	row, col int
+	untried  uint32
+	val      byte
+	mark     int
+}
+
+// SolveIterative solves the puzzle in place like Solve, but keeps the search
+// on an explicit stack instead of recursing. It visits the same cells in the
+// same order as Solve and follows SetRandom and SetNodeLimit in the same
+// way, so both find the same solution.
+func (p *Puzzle) SolveIterative() bool {
+	s := p.newSearch(context.Background())
+	var stack []frame
+	for {
+		if s.interrupted() {
+			// Unwind every guess so the puzzle is left as it was.
+			for i := len(stack) - 1; i >= 0; i-- {
+				p.clearCell(stack[i].row, stack[i].col, stack[i].val)
+				p.undo(stack[i].mark)
+			}
+			return false
+		}
+		mark := len(p.trail)
+		if p.propagate() {
+			row, col, poss, found := p.selectCell()
+			if !found {
+				p.commit()
+				return true
+			}
+			stack = append(stack, frame{row: row, col: col, untried: poss, mark: mark})
+		} else {
+			p.undo(mark)
+		}
+
+		// Move to the next untried digit, popping exhausted frames.
+		for {
+			if len(stack) == 0 {
+				return false
+			}
+			f := &stack[len(stack)-1]
+			if f.val != 0 {
+				p.clearCell(f.row, f.col, f.val)
+				f.val = 0
+			}
+			if f.untried == 0 {
+				p.undo(f.mark)
+				stack = stack[:len(stack)-1]
+				continue
+			}
+			f.val = byte(pickDigit(s.rng, f.untried))
+			f.untried &^= 1 << (f.val - 1)
+			p.setCell(f.row, f.col, f.val)
+			break
+		}
+	}
+}
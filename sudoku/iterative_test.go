@@ -0,0 +1,70 @@
+package sudoku
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSolveIterativeMatchesSolve(t *testing.T) {
+	inputs := []string{
+		// No solution: row 1 needs a 9 that column 9 already holds.
+		"12345678.........9...............................................................",
+	}
+	for _, bp := range benchPuzzles {
+		inputs = append(inputs, bp.puzzle)
+	}
+
+	for _, input := range inputs {
+		recursive, err := ParsePuzzle(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iterative, _ := ParsePuzzle(input)
+
+		want := recursive.Solve()
+		if got := iterative.SolveIterative(); got != want {
+			t.Errorf("%s: SolveIterative() = %v, Solve() = %v", input, got, want)
+		}
+		if got, want := iterative.ToString(), recursive.ToString(); got != want {
+			t.Errorf("%s: SolveIterative grid %s, Solve grid %s", input, got, want)
+		}
+	}
+}
+
+func TestSolveIterativeRandom(t *testing.T) {
+	for seed := int64(1); seed <= 5; seed++ {
+		recursive, err := NewPuzzle(SIZE)
+		if err != nil {
+			t.Fatal(err)
+		}
+		iterative, _ := NewPuzzle(SIZE)
+		recursive.SetRandom(rand.New(rand.NewSource(seed)))
+		iterative.SetRandom(rand.New(rand.NewSource(seed)))
+
+		if !recursive.Solve() || !iterative.SolveIterative() {
+			t.Fatalf("seed %d: empty board not solved", seed)
+		}
+		if got, want := iterative.ToString(), recursive.ToString(); got != want {
+			t.Errorf("seed %d: SolveIterative grid %s, Solve grid %s", seed, got, want)
+		}
+	}
+}
+
+func TestSolveIterativeNodeLimit(t *testing.T) {
+	input := benchPuzzles[2].puzzle
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetNodeLimit(1)
+	if p.SolveIterative() {
+		t.Fatal("SolveIterative solved past a node limit of 1")
+	}
+	if got := p.ToString(); got != input {
+		t.Errorf("SolveIterative left %s after hitting the node limit", got)
+	}
+	p.SetNodeLimit(0)
+	if !p.SolveIterative() {
+		t.Error("SolveIterative found no solution without a limit")
+	}
+}
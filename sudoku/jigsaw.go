@@ -0,0 +1,95 @@
+package sudoku
+
+import "fmt"
+
+// SetRegions turns the puzzle into a Jigsaw Sudoku, replacing the boxes with
+// irregular regions. regions[row][col] is the region of each cell, numbered
+// from 0, and every region must have exactly N cells joined edge to edge.
+// Passing nil restores the classic boxes.
+func (p *Puzzle) SetRegions(regions [][]int) error {
+	if regions == nil {
+		p.layout = layouts[p.size]
+		p.rebuildBoxes()
+		p.indexExtras()
+		return nil
+	}
+
+	if len(regions) != p.size {
+		return fmt.Errorf("%w: regions have %d rows, want %d", ErrInvalid, len(regions), p.size)
+	}
+	boxOf := make([]int, p.numCells)
+	counts := make([]int, p.size)
+	for i, row := range regions {
+		if len(row) != p.size {
+			return fmt.Errorf("%w: regions row %d has %d cells, want %d", ErrInvalid, i+1, len(row), p.size)
+		}
+		for j, r := range row {
+			if r < 0 || r >= p.size {
+				return fmt.Errorf("%w: cell (%d, %d) is in region %d, want 0 to %d", ErrInvalid, i, j, r, p.size-1)
+			}
+			boxOf[i*p.size+j] = r
+			counts[r]++
+		}
+	}
+	for r, n := range counts {
+		if n != p.size {
+			return fmt.Errorf("%w: region %d has %d cells, want %d", ErrInvalid, r, n, p.size)
+		}
+	}
+	if r, ok := connected(boxOf, p.size); !ok {
+		return fmt.Errorf("%w: region %d is split into separate pieces", ErrInvalid, r)
+	}
+
+	l := *layouts[p.size]
+	l.boxOf = boxOf
+	l.buildUnits()
+	p.layout = &l
+	p.rebuildBoxes()
+	p.indexExtras()
+	return nil
+}
+
+// rebuildBoxes recomputes the box masks from the cells after the regions
+// change.
+func (p *Puzzle) rebuildBoxes() {
+	clear(p.boxes)
+	for idx, val := range p.cells {
+		if val != 0 {
+			p.boxes[p.boxOf[idx]] |= 1 << (val - 1)
+		}
+	}
+}
+
+// connected reports whether every region of boxOf on a size x size board is
+// one piece of edge-adjacent cells, and if not, which region is split.
+func connected(boxOf []int, size int) (int, bool) {
+	seen := make([]bool, len(boxOf))
+	reached := make([]bool, size)
+	var stack []int
+	for start, r := range boxOf {
+		if seen[start] {
+			continue
+		}
+		if reached[r] {
+			return r, false
+		}
+		reached[r] = true
+		seen[start] = true
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			idx := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			row, col := idx/size, idx%size
+			for _, next := range [4][2]int{{row - 1, col}, {row + 1, col}, {row, col - 1}, {row, col + 1}} {
+				if next[0] < 0 || next[0] >= size || next[1] < 0 || next[1] >= size {
+					continue
+				}
+				if n := next[0]*size + next[1]; !seen[n] && boxOf[n] == r {
+					seen[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+	}
+	return 0, true
+}
@@ -0,0 +1,95 @@
+package sudoku
+
+import (
+	"errors"
+	"testing"
+)
+
+// jigsawMap is a 9x9 region layout, one digit per cell.
+var jigsawMap = []string{
+	"000112222",
+	"000111112",
+	"030142222",
+	"033144445",
+	"333444455",
+	"663555555",
+	"663377778",
+	"667777788",
+	"666888888",
+}
+
+func regionsFromMap(rows []string) [][]int {
+	regions := make([][]int, len(rows))
+	for i, row := range rows {
+		for _, ch := range row {
+			regions[i] = append(regions[i], int(ch-'0'))
+		}
+	}
+	return regions
+}
+
+func TestSetRegionsSolve(t *testing.T) {
+	regions := regionsFromMap(jigsawMap)
+	p, err := NewPuzzle(SIZE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetRegions(regions); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Solve() {
+		t.Fatal("no solution for an empty jigsaw board")
+	}
+	if err := p.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	seen := make([]uint32, SIZE)
+	for i, row := range regions {
+		for j, r := range row {
+			val, _ := p.At(i, j)
+			if seen[r]&(1<<val) != 0 {
+				t.Errorf("region %d repeats %d:\n%s", r, val, p)
+			}
+			seen[r] |= 1 << val
+		}
+	}
+}
+
+func TestSetRegionsRejects(t *testing.T) {
+	classic := func() [][]int {
+		regions := make([][]int, SIZE)
+		for i := range regions {
+			for j := 0; j < SIZE; j++ {
+				regions[i] = append(regions[i], i/3*3+j/3)
+			}
+		}
+		return regions
+	}
+	tests := []struct {
+		name   string
+		change func(regions [][]int) [][]int
+	}{
+		{"too few rows", func(r [][]int) [][]int { return r[1:] }},
+		{"short row", func(r [][]int) [][]int { r[4] = r[4][1:]; return r }},
+		{"region out of range", func(r [][]int) [][]int { r[0][0] = SIZE; return r }},
+		{"negative region", func(r [][]int) [][]int { r[0][0] = -1; return r }},
+		{"missing region", func(r [][]int) [][]int {
+			for i := 6; i < 9; i++ {
+				for j := 6; j < 9; j++ {
+					r[i][j] = 7
+				}
+			}
+			return r
+		}},
+		{"split region", func(r [][]int) [][]int { r[0][0], r[8][8] = r[8][8], r[0][0]; return r }},
+	}
+	for _, tt := range tests {
+		p, err := NewPuzzle(SIZE)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.SetRegions(tt.change(classic())); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: SetRegions error = %v, want ErrInvalid", tt.name, err)
+		}
+	}
+}
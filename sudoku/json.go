@@ -0,0 +1,59 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MarshalJSON encodes the board as an array of rows, each an array of
+// digits with 0 for empty cells.
+func (p *Puzzle) MarshalJSON() ([]byte, error) {
+	grid := make([][]int, p.size)
+	for i := range grid {
+		grid[i] = make([]int, p.size)
+		for j := range grid[i] {
+			grid[i][j] = int(p.cells[i*p.size+j])
+		}
+	}
+	return json.Marshal(grid)
+}
+
+// UnmarshalJSON decodes a board written by MarshalJSON. The board size is
+// taken from the number of rows.
+func (p *Puzzle) UnmarshalJSON(data []byte) error {
+	var grid [][]int
+	if err := json.Unmarshal(data, &grid); err != nil {
+		return err
+	}
+	q, err := fromGrid(grid)
+	if err != nil {
+		return err
+	}
+	*p = *q
+	return nil
+}
+
+// fromGrid builds a puzzle from rows of digits, where 0 marks an empty cell.
+func fromGrid(grid [][]int) (*Puzzle, error) {
+	l, ok := layouts[len(grid)]
+	if !ok {
+		return nil, fmt.Errorf("%w: grid has %d rows, not a supported board size", ErrInvalid, len(grid))
+	}
+
+	p := newPuzzle(l)
+	for i, row := range grid {
+		if len(row) != l.size {
+			return nil, fmt.Errorf("%w: row %d has %d cells, want %d", ErrInvalid, i+1, len(row), l.size)
+		}
+		for j, val := range row {
+			if val < 0 || val > l.size {
+				return nil, fmt.Errorf("%w: invalid digit %d at row %d, column %d", ErrInvalid, val, i+1, j+1)
+			}
+			if val != 0 {
+				p.setCell(i, j, byte(val))
+			}
+		}
+	}
+	p.markGivens()
+	return p, nil
+}
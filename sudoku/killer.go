@@ -0,0 +1,127 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/bits"
+)
+
+// Cage is a Killer Sudoku group of cells whose digits must be distinct and
+// add up to Sum. Cells are (row, col) pairs.
+type Cage struct {
+	Cells [][2]int
+	Sum   int
+}
+
+// cageState tracks the digits placed so far in one cage.
+type cageState struct {
+	sum   int    // target sum
+	used  uint32 // digits placed in the cage
+	total int    // sum of the placed digits
+	empty int    // cells still empty
+}
+
+// SetCages turns the puzzle into a Killer Sudoku with the given cages, which
+// must partition the grid and have sums that some set of distinct digits can
+// reach. Candidates that would make a cage sum unreachable are then pruned
+// during the search. Passing nil removes the cages.
+func (p *Puzzle) SetCages(cages []Cage) error {
+	if cages == nil {
+		p.cages, p.cageOf = nil, nil
+		return nil
+	}
+
+	cageOf := make([]int, p.numCells)
+	for i := range cageOf {
+		cageOf[i] = -1
+	}
+	states := make([]cageState, len(cages))
+	total := 0
+	for n, cage := range cages {
+		k := len(cage.Cells)
+		if k == 0 || k > p.size {
+			return fmt.Errorf("%w: cage %d has %d cells", ErrInvalid, n+1, k)
+		}
+		if !sumReachable(cage.Sum, k, p.allBits) {
+			return fmt.Errorf("%w: cage %d cannot sum to %d with %d distinct digits", ErrInvalid, n+1, cage.Sum, k)
+		}
+		total += cage.Sum
+
+		c := &states[n]
+		c.sum = cage.Sum
+		for _, cell := range cage.Cells {
+			row, col := cell[0], cell[1]
+			if row < 0 || row >= p.size || col < 0 || col >= p.size {
+				return fmt.Errorf("%w: cage %d has cell (%d, %d) outside the grid", ErrInvalid, n+1, row, col)
+			}
+			idx := row*p.size + col
+			if cageOf[idx] != -1 {
+				return fmt.Errorf("%w: cell (%d, %d) is in cages %d and %d", ErrInvalid, row, col, cageOf[idx]+1, n+1)
+			}
+			cageOf[idx] = n
+
+			val := p.cells[idx]
+			if val == 0 {
+				c.empty++
+				continue
+			}
+			if c.used&(1<<(val-1)) != 0 {
+				return fmt.Errorf("%w: digit %c repeated in cage %d", ErrInvalid, DIGITS[val-1], n+1)
+			}
+			c.used |= 1 << (val - 1)
+			c.total += int(val)
+		}
+		if c.total > c.sum || (c.empty == 0 && c.total != c.sum) {
+			return fmt.Errorf("%w: givens in cage %d do not fit its sum of %d", ErrInvalid, n+1, c.sum)
+		}
+	}
+
+	for idx, n := range cageOf {
+		if n == -1 {
+			return fmt.Errorf("%w: cell (%d, %d) is not in any cage", ErrInvalid, idx/p.size, idx%p.size)
+		}
+	}
+	if want := p.size * p.size * (p.size + 1) / 2; total != want {
+		return fmt.Errorf("%w: cage sums add up to %d, want %d", ErrInvalid, total, want)
+	}
+
+	p.cages, p.cageOf = states, cageOf
+	return nil
+}
+
+// cagePossibilities removes from poss the digits that cannot go in the cell
+// at idx without repeating a digit in its cage or making the cage sum
+// unreachable.
+func (p *Puzzle) cagePossibilities(idx int, poss uint32) uint32 {
+	c := &p.cages[p.cageOf[idx]]
+	poss &^= c.used
+	for m := poss; m != 0; m &= m - 1 {
+		bit := m & -m
+		rest := c.sum - c.total - (bits.TrailingZeros32(m) + 1)
+		if !sumReachable(rest, c.empty-1, p.allBits&^c.used&^bit) {
+			poss &^= bit
+		}
+	}
+	return poss
+}
+
+// sumReachable reports whether sum lies between the smallest and largest
+// totals of k distinct digits taken from avail.
+func sumReachable(sum, k int, avail uint32) bool {
+	if k == 0 {
+		return sum == 0
+	}
+	if bits.OnesCount32(avail) < k {
+		return false
+	}
+
+	low, high := 0, 0
+	lo, hi := avail, avail
+	for i := 0; i < k; i++ {
+		low += bits.TrailingZeros32(lo) + 1
+		lo &= lo - 1
+		top := 31 - bits.LeadingZeros32(hi)
+		high += top + 1
+		hi &^= 1 << top
+	}
+	return low <= sum && sum <= high
+}
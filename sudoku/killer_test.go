@@ -0,0 +1,115 @@
+package sudoku
+
+import (
+	"errors"
+	"testing"
+)
+
+// killerCages lays out a Killer Sudoku with no givens whose unique solution
+// is that of the easy puzzle. Each letter is a cage; killerSums gives the
+// cage totals.
+var killerCages = []string{
+	"LbCCBMMMW",
+	"LbgBBBRXX",
+	"GGPhddXXj",
+	"SGJhFFaII",
+	"SJJmKKKiN",
+	"cJEEDKleN",
+	"cTEEDZZeN",
+	"VTTYQOOfA",
+	"VnkUUHHHA",
+}
+
+var killerSums = map[byte]int{
+	'A': 14, 'B': 22, 'C': 10, 'D': 5, 'E': 18, 'F': 7, 'G': 15, 'H': 14, 'I': 5, 'J': 18,
+	'K': 19, 'L': 11, 'M': 18, 'N': 11, 'O': 15, 'P': 8, 'Q': 1, 'R': 3, 'S': 12, 'T': 21,
+	'U': 10, 'V': 5, 'W': 2, 'X': 23, 'Y': 4, 'Z': 9, 'a': 4, 'b': 10, 'c': 16, 'd': 6,
+	'e': 13, 'f': 3, 'g': 2, 'h': 10, 'i': 9, 'j': 7, 'k': 5, 'l': 8, 'm': 8, 'n': 4,
+}
+
+// cagesFromMap builds the cages drawn by a map of cage letters.
+func cagesFromMap(rows []string, sums map[byte]int) []Cage {
+	var cages []Cage
+	index := map[byte]int{}
+	for i, row := range rows {
+		for j := 0; j < len(row); j++ {
+			n, ok := index[row[j]]
+			if !ok {
+				n = len(cages)
+				index[row[j]] = n
+				cages = append(cages, Cage{Sum: sums[row[j]]})
+			}
+			cages[n].Cells = append(cages[n].Cells, [2]int{i, j})
+		}
+	}
+	return cages
+}
+
+func TestSetCagesSolve(t *testing.T) {
+	cages := cagesFromMap(killerCages, killerSums)
+	p, err := NewPuzzle(SIZE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetCages(cages); err != nil {
+		t.Fatal(err)
+	}
+	if n := p.CountSolutions(2); n != 1 {
+		t.Fatalf("killer puzzle has %d solutions, want 1", n)
+	}
+	if !p.Solve() {
+		t.Fatal("no solution")
+	}
+	if got, want := p.ToString(), knownSolutions[0].solution; got != want {
+		t.Errorf("Solve = %s, want %s", got, want)
+	}
+	for n, cage := range cages {
+		sum, seen := 0, uint32(0)
+		for _, cell := range cage.Cells {
+			val, _ := p.At(cell[0], cell[1])
+			if seen&(1<<val) != 0 {
+				t.Errorf("cage %d repeats %d", n+1, val)
+			}
+			seen |= 1 << val
+			sum += val
+		}
+		if sum != cage.Sum {
+			t.Errorf("cage %d sums to %d, want %d", n+1, sum, cage.Sum)
+		}
+	}
+}
+
+func TestSetCagesRejects(t *testing.T) {
+	valid := func() []Cage { return cagesFromMap(killerCages, killerSums) }
+	tests := []struct {
+		name  string
+		cages func() []Cage
+	}{
+		{"overlap", func() []Cage {
+			c := valid()
+			c[0].Cells = append(c[0].Cells, c[1].Cells[0])
+			return c
+		}},
+		{"outside", func() []Cage {
+			c := valid()
+			c[0].Cells = append(c[0].Cells, [2]int{SIZE, 0})
+			return c
+		}},
+		{"uncovered", func() []Cage { return valid()[1:] }},
+		{"unreachable sum", func() []Cage {
+			c := valid()
+			c[0].Sum = 1
+			return c
+		}},
+		{"empty cage", func() []Cage { return append(valid(), Cage{Sum: 3}) }},
+	}
+	for _, tt := range tests {
+		p, err := NewPuzzle(SIZE)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.SetCages(tt.cages()); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: SetCages error = %v, want ErrInvalid", tt.name, err)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package sudoku
+
+// Level sets how much deduction SolveLevel attempts. Each level includes
+// the techniques of the ones before it.
+type Level int
+
+const (
+	// BasicOnly uses naked and hidden singles and naked pairs and triples.
+	BasicOnly Level = iota
+	// Intermediate adds hidden pairs and box-line reduction.
+	Intermediate
+	// Advanced adds X-wings and swordfish.
+	Advanced
+	// BruteForce falls back to guessing once deduction stalls, as Solve
+	// does, so it solves every puzzle that has a solution.
+	BruteForce
+)
+
+var levelNames = [...]string{"basic", "intermediate", "advanced", "brute force"}
+
+func (l Level) String() string {
+	if l < 0 || int(l) >= len(levelNames) {
+		return "unknown"
+	}
+	return levelNames[l]
+}
+
+// SolveLevel fills the puzzle in place using the techniques up to level and
+// reports whether it was solved. Below BruteForce nothing is guessed: if
+// deduction stalls before the board is full, it is left as it was and
+// SolveLevel returns false. Settings such as SetFish are restored
+// afterwards.
+func (p *Puzzle) SolveLevel(level Level) bool {
+	if level >= BruteForce {
+		return p.solve()
+	}
+
+	hiddenPairs, boxLine, fish := p.hiddenPairs, p.boxLine, p.fish
+	defer func() { p.hiddenPairs, p.boxLine, p.fish = hiddenPairs, boxLine, fish }()
+	p.hiddenPairs = level >= Intermediate
+	p.boxLine = level >= Intermediate
+	p.fish = 0
+	if level >= Advanced {
+		p.fish = 3
+	}
+
+	mark := len(p.trail)
+	if p.propagate() && p.emptyCell == 0 {
+		p.commit()
+		return true
+	}
+	p.undo(mark)
+	return false
+}
+
+// MinLevel returns the lowest level at which SolveLevel solves the puzzle
+// without guessing, or BruteForce if none does. The receiver is not
+// modified.
+func (p *Puzzle) MinLevel() Level {
+	for level := BasicOnly; level < BruteForce; level++ {
+		if p.Clone().SolveLevel(level) {
+			return level
+		}
+	}
+	return BruteForce
+}
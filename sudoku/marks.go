@@ -0,0 +1,127 @@
+package sudoku
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseMarked reads a board like ParsePuzzle, but any cell may instead be a
+// set of candidates in braces, such as "{1,4,7}" or "{147}", that limits
+// which digits the solver may place there. A set starting with '^', such as
+// "{^5}", lists forbidden digits instead, for negative clues. Whitespace
+// between cells is ignored. This reads mid-solve states exported from other
+// programs.
+func ParseMarked(input string) (*Puzzle, error) {
+	type cell struct {
+		val     byte
+		allowed []byte
+		forbid  bool
+	}
+	var cells []cell
+	for i := 0; i < len(input); i++ {
+		ch := input[i]
+		switch {
+		case ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r':
+		case ch == '{':
+			end := strings.IndexByte(input[i:], '}')
+			if end < 0 {
+				return nil, fmt.Errorf("%w: unclosed '{' at index %d", ErrInvalid, i)
+			}
+			var c cell
+			start := i + 1
+			if start < i+end && input[start] == '^' {
+				c.forbid = true
+				start++
+			}
+			for j := start; j < i+end; j++ {
+				if input[j] == ',' || input[j] == ' ' {
+					continue
+				}
+				val := digitValue(input[j])
+				if val == 0 || int(val) > MAX_SIZE {
+					return nil, fmt.Errorf("%w: invalid candidate %q at index %d", ErrInvalid, input[j], j)
+				}
+				c.allowed = append(c.allowed, val)
+			}
+			if len(c.allowed) == 0 {
+				return nil, fmt.Errorf("%w: empty candidate set at index %d", ErrInvalid, i)
+			}
+			cells = append(cells, c)
+			i += end
+		case isEmpty(ch):
+			cells = append(cells, cell{})
+		default:
+			val := digitValue(ch)
+			if val == 0 || int(val) > MAX_SIZE {
+				return nil, fmt.Errorf("%w: invalid character %q at index %d", ErrInvalid, ch, i)
+			}
+			cells = append(cells, cell{val: val})
+		}
+	}
+
+	size := SizeFor(len(cells))
+	if size == 0 {
+		return nil, fmt.Errorf("%w: puzzle has %d cells, not a supported board size", ErrInvalid, len(cells))
+	}
+	p := newPuzzle(layouts[size])
+	for idx, c := range cells {
+		row, col := idx/size, idx%size
+		if int(c.val) > size {
+			return nil, fmt.Errorf("%w: digit %c out of range in row %d, column %d", ErrInvalid, DIGITS[c.val-1], row+1, col+1)
+		}
+		if c.val != 0 {
+			p.setCell(row, col, c.val)
+		}
+		if c.allowed == nil {
+			continue
+		}
+		p.initAllowed()
+		var mask uint32
+		for _, val := range c.allowed {
+			if int(val) > size {
+				return nil, fmt.Errorf("%w: candidate %c out of range in row %d, column %d", ErrInvalid, DIGITS[val-1], row+1, col+1)
+			}
+			mask |= 1 << (val - 1)
+		}
+		if c.forbid {
+			mask = p.allBits &^ mask
+			if mask == 0 {
+				return nil, fmt.Errorf("%w: every digit is forbidden in row %d, column %d", ErrInvalid, row+1, col+1)
+			}
+		}
+		p.allowed[idx] = mask
+	}
+	p.markGivens()
+	return p, nil
+}
+
+// Forbid rules out digits at (row, col), counted from 0, as a negative
+// clue: the solver treats them as already eliminated there. Only empty
+// cells are affected; a digit already on the board is left in place.
+func (p *Puzzle) Forbid(row, col int, digits ...int) error {
+	if err := p.checkCell(row, col); err != nil {
+		return err
+	}
+	var mask uint32
+	for _, d := range digits {
+		if d < 1 || d > p.size {
+			return fmt.Errorf("sudoku: digit %d out of range for a %dx%d board", d, p.size, p.size)
+		}
+		mask |= 1 << (d - 1)
+	}
+	p.initAllowed()
+	p.allowed[row*p.size+col] &^= mask
+	return nil
+}
+
+// initAllowed creates p.allowed with every digit permitted if it is not
+// already set.
+func (p *Puzzle) initAllowed() {
+	if p.allowed != nil {
+		return
+	}
+	p.allowed = make([]uint32, p.numCells)
+	for i := range p.allowed {
+		p.allowed[i] = p.allBits
+	}
+}
@@ -0,0 +1,96 @@
+package sudoku
+
+import (
+	"context"
+	"math/bits"
+	"math/rand"
+	"runtime"
+	"sync"
+)
+
+// SolveParallel solves p in place like Solve, but searches the candidates of
+// the first guessed cell concurrently, each on its own copy of the board, with
+// at most workers searches at a time (one per CPU if workers <= 0). Once a
+// branch succeeds, the branches for higher digits are cancelled and the
+// lowest successful digit wins. Without SetRandom that is the solution Solve
+// would find; with it, each branch draws from its own source seeded from
+// the puzzle's, so the result is reproducible but generally differs from
+// Solve's. A board with a custom CellSelector is solved by Solve instead,
+// since the selector may hold state that cannot be shared between branches.
+func SolveParallel(p *Puzzle, workers int) bool {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	if p.selector != nil {
+		return p.Solve()
+	}
+
+	root := p.Clone()
+	if !root.propagate() {
+		return false
+	}
+	row, col, poss, found := root.selectCell()
+	if !found {
+		root.commit()
+		p.copyFrom(root)
+		return true
+	}
+	rng := p.rng
+
+	ctx, cancelAll := context.WithCancel(context.Background())
+	defer cancelAll()
+
+	type branch struct {
+		puzzle *Puzzle
+		ctx    context.Context
+		cancel context.CancelFunc
+		solved bool
+	}
+	var branches []*branch
+	for ; poss != 0; poss &= poss - 1 {
+		b := &branch{puzzle: root.Clone()}
+		if rng != nil {
+			b.puzzle.rng = rand.New(rand.NewSource(rng.Int63()))
+		}
+		b.puzzle.setCell(row, col, byte(bits.TrailingZeros32(poss)+1))
+		b.ctx, b.cancel = context.WithCancel(ctx)
+		branches = append(branches, b)
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, workers)
+	for i, b := range branches {
+		wg.Add(1)
+		go func(i int, b *branch) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if b.ctx.Err() != nil {
+				return
+			}
+
+			solved, _ := b.puzzle.SolveContext(b.ctx)
+			if !solved {
+				return
+			}
+			mu.Lock()
+			b.solved = true
+			for _, later := range branches[i+1:] {
+				later.cancel()
+			}
+			mu.Unlock()
+		}(i, b)
+	}
+	wg.Wait()
+
+	for _, b := range branches {
+		b.cancel()
+		if b.solved {
+			p.copyFrom(b.puzzle)
+			p.rng = rng
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package sudoku
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func TestSolveParallelMatchesSolve(t *testing.T) {
+	for _, bp := range benchPuzzles {
+		want, err := SolveString(bp.puzzle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p, err := ParsePuzzle(bp.puzzle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !SolveParallel(p, 4) {
+			t.Errorf("%s: SolveParallel found no solution", bp.name)
+			continue
+		}
+		if got := p.ToString(); got != want {
+			t.Errorf("%s: SolveParallel = %s, want %s", bp.name, got, want)
+		}
+	}
+}
+
+// TestSolveParallelRandom runs the branches of a randomized search at once;
+// under -race it checks that they do not share a random source.
+func TestSolveParallelRandom(t *testing.T) {
+	solve := func() string {
+		p, err := NewPuzzle(16)
+		if err != nil {
+			t.Fatal(err)
+		}
+		p.SetRandom(rand.New(rand.NewSource(7)))
+		if !SolveParallel(p, 4) {
+			t.Fatal("SolveParallel found no solution for an empty 16x16 board")
+		}
+		if p.EmptyCount() != 0 || p.Validate() != nil {
+			t.Fatalf("SolveParallel left an incomplete or invalid board:\n%s", p)
+		}
+		return p.ToString()
+	}
+	if a, b := solve(), solve(); a != b {
+		t.Errorf("SolveParallel with the same seed gave %s and %s", a, b)
+	}
+
+	p, err := NewPuzzle(SIZE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.SetCellSelector(RandomMinRemaining(rand.New(rand.NewSource(7))))
+	if !SolveParallel(p, 4) || p.EmptyCount() != 0 {
+		t.Error("SolveParallel with a custom selector did not solve an empty board")
+	}
+}
@@ -0,0 +1,396 @@
+package sudoku
+
+import "math/bits"
+
+// move is one entry of the undo trail: a placed cell when elim is zero,
+// otherwise the candidates eliminated from the cell.
+type move struct {
+	idx  int
+	elim uint32
+}
+
+// propagate applies logical deductions until none of them makes progress:
+// naked and hidden singles fill cells, and naked pairs and triples (and
+// box-line reduction, hidden pairs and fish, if enabled) eliminate
+// candidates. It returns false if the board reaches a contradiction. Every
+// change is pushed onto p.trail so the caller can undo it.
+func (p *Puzzle) propagate() bool {
+	for {
+		if !p.propagateSingles() {
+			return false
+		}
+		if p.emptyCell == 0 {
+			return true
+		}
+		changed := p.nakedSubsets()
+		if p.boxLine && p.eliminateIntersections() {
+			changed = true
+		}
+		if p.hiddenPairs && p.eliminateHiddenPairs() {
+			changed = true
+		}
+		if p.fish >= 2 && p.eliminateFish(p.fish) {
+			changed = true
+		}
+		if !changed {
+			return true
+		}
+	}
+}
+
+// propagateSingles places naked singles (cells with one candidate) and
+// hidden singles (digits with one possible cell in a unit) until nothing
+// changes. It returns false on a contradiction: a cell with no candidates,
+// or a unit with a missing digit that none of its empty cells can take.
+func (p *Puzzle) propagateSingles() bool {
+	for changed := true; changed; {
+		changed = false
+
+		for idx, val := range p.cells {
+			if val != 0 {
+				continue
+			}
+			row, col := idx/p.size, idx%p.size
+			poss := p.getPossibilities(row, col)
+			if poss == 0 {
+				return false
+			}
+			if poss&(poss-1) == 0 {
+				p.place(idx, byte(bits.TrailingZeros32(poss)+1), NakedSingle)
+				changed = true
+			}
+		}
+
+		for _, unit := range p.unitList() {
+			var placed, once, twice uint32
+			for _, idx := range unit {
+				if val := p.cells[idx]; val != 0 {
+					placed |= 1 << (val - 1)
+					continue
+				}
+				poss := p.getPossibilities(idx/p.size, idx%p.size)
+				twice |= once & poss
+				once |= poss
+			}
+			if once|placed != p.allBits {
+				return false
+			}
+
+			for singles := once &^ twice; singles != 0; singles &= singles - 1 {
+				bit := singles & -singles
+				placedSingle := false
+				for _, idx := range unit {
+					if p.cells[idx] == 0 && p.getPossibilities(idx/p.size, idx%p.size)&bit != 0 {
+						p.place(idx, byte(bits.TrailingZeros32(bit)+1), HiddenSingle)
+						placedSingle = true
+						break
+					}
+				}
+				// An earlier single in this unit took the digit's only
+				// cell, so the digit now has nowhere to go.
+				if !placedSingle {
+					return false
+				}
+				changed = true
+			}
+		}
+	}
+	return true
+}
+
+// nakedSubsets looks in every unit for two cells whose candidates are the
+// same two digits, or three cells whose candidates fall within three digits,
+// and removes those digits from the other cells of the unit. It reports
+// whether any candidate was eliminated.
+func (p *Puzzle) nakedSubsets() bool {
+	changed := false
+	var cells [MAX_SIZE]int
+	var masks [MAX_SIZE]uint32
+	for _, unit := range p.unitList() {
+		n := 0
+		for _, idx := range unit {
+			if p.cells[idx] == 0 {
+				cells[n] = idx
+				masks[n] = p.getPossibilities(idx/p.size, idx%p.size)
+				n++
+			}
+		}
+
+		for a := 0; a < n; a++ {
+			if bits.OnesCount32(masks[a]) > 3 {
+				continue
+			}
+			for b := a + 1; b < n; b++ {
+				ab := masks[a] | masks[b]
+				switch bits.OnesCount32(ab) {
+				case 2:
+					if p.eliminateOutside(cells[:n], masks[:n], 1<<a|1<<b, ab) {
+						changed = true
+					}
+				case 3:
+					for c := b + 1; c < n; c++ {
+						if abc := ab | masks[c]; bits.OnesCount32(abc) == 3 {
+							if p.eliminateOutside(cells[:n], masks[:n], 1<<a|1<<b|1<<c, abc) {
+								changed = true
+							}
+						}
+					}
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// SetHiddenPairs turns hidden pair elimination on or off. It is off by
+// default, so its value can be measured against singles and naked subsets
+// alone; it saves search on some puzzles but costs an extra scan of every
+// unit on each pass.
+func (p *Puzzle) SetHiddenPairs(on bool) {
+	p.hiddenPairs = on
+}
+
+// eliminateHiddenPairs looks in every unit for two digits that can only go
+// in the same two cells and removes every other candidate from those cells.
+// It reports whether any candidate was eliminated.
+func (p *Puzzle) eliminateHiddenPairs() bool {
+	changed := false
+	var cells [MAX_SIZE]int
+	var masks [MAX_SIZE]uint32
+	// where[d] holds the positions within the unit that can take digit d+1.
+	var where [MAX_SIZE]uint32
+	for _, unit := range p.unitList() {
+		clear(where[:p.size])
+		n := 0
+		for _, idx := range unit {
+			if p.cells[idx] != 0 {
+				continue
+			}
+			cells[n] = idx
+			masks[n] = p.getPossibilities(idx/p.size, idx%p.size)
+			for poss := masks[n]; poss != 0; poss &= poss - 1 {
+				where[bits.TrailingZeros32(poss)] |= 1 << n
+			}
+			n++
+		}
+
+		for d := 0; d < p.size; d++ {
+			if bits.OnesCount32(where[d]) != 2 {
+				continue
+			}
+			for e := d + 1; e < p.size; e++ {
+				if where[e] != where[d] {
+					continue
+				}
+				pair := uint32(1)<<d | 1<<e
+				for pos := where[d]; pos != 0; pos &= pos - 1 {
+					i := bits.TrailingZeros32(pos)
+					if extra := masks[i] &^ pair; extra != 0 {
+						p.eliminate(cells[i], extra)
+						masks[i] &^= extra
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// SetBoxLineReduction turns pointing pair and box-line reduction
+// eliminations on or off. Like SetHiddenPairs it is off by default: it
+// trims the search tree but the extra scans cost more than they save on
+// most classic puzzles.
+func (p *Puzzle) SetBoxLineReduction(on bool) {
+	p.boxLine = on
+}
+
+// rowKind, colKind and boxKind number the unit kinds in the order of layout.units:
+// rows, then columns, then boxes.
+const (
+	rowKind = iota
+	colKind
+	boxKind
+)
+
+// intersections lists the unit kinds whose overlaps eliminateIntersections
+// examines: pointing pairs look from a box at a line, box-line reduction
+// from a line at a box.
+var intersections = [][2]int{{boxKind, rowKind}, {boxKind, colKind}, {rowKind, boxKind}, {colKind, boxKind}}
+
+// unitOf returns the number of the unit of the given kind that holds idx.
+func (p *Puzzle) unitOf(kind, idx int) int {
+	switch kind {
+	case rowKind:
+		return idx / p.size
+	case colKind:
+		return idx % p.size
+	}
+	return p.boxOf[idx]
+}
+
+// eliminateIntersections finds digits whose candidate cells within one unit
+// all lie in a single crossing unit, a box and a row or column, and removes
+// the digit from the rest of that crossing unit. It reports whether any
+// candidate was eliminated.
+func (p *Puzzle) eliminateIntersections() bool {
+	changed := false
+	for _, kinds := range intersections {
+		src, cross := kinds[0], kinds[1]
+		for u := 0; u < p.size; u++ {
+			// seen[d] holds the crossing units that can take digit d+1.
+			var seen [MAX_SIZE]uint32
+			for _, idx := range p.units[src*p.size+u] {
+				if p.cells[idx] != 0 {
+					continue
+				}
+				k := uint32(1) << p.unitOf(cross, idx)
+				for poss := p.getPossibilities(idx/p.size, idx%p.size); poss != 0; poss &= poss - 1 {
+					seen[bits.TrailingZeros32(poss)] |= k
+				}
+			}
+
+			for d := 0; d < p.size; d++ {
+				if seen[d] == 0 || seen[d]&(seen[d]-1) != 0 {
+					continue
+				}
+				bit := uint32(1) << d
+				for _, idx := range p.units[cross*p.size+bits.TrailingZeros32(seen[d])] {
+					if p.cells[idx] == 0 && p.unitOf(src, idx) != u && p.getPossibilities(idx/p.size, idx%p.size)&bit != 0 {
+						p.eliminate(idx, bit)
+						changed = true
+					}
+				}
+			}
+		}
+	}
+	return changed
+}
+
+// SetFish enables fish eliminations up to the given size: 2 looks for
+// X-wings, 3 also for swordfish, and anything below 2 turns them off, the
+// default. A fish of size n is n rows in which a digit can only go in the
+// same n columns, or the same with rows and columns swapped; the digit is
+// then removed from the rest of those columns.
+func (p *Puzzle) SetFish(size int) {
+	p.fish = size
+}
+
+// eliminateFish finds fish of every size from 2 to maxSize, for each digit
+// and both orientations, and reports whether any candidate was eliminated.
+func (p *Puzzle) eliminateFish(maxSize int) bool {
+	changed := false
+	for d := 0; d < p.size; d++ {
+		bit := uint32(1) << d
+		for _, base := range [2]int{rowKind, colKind} {
+			cover := rowKind + colKind - base
+
+			// lines[u] holds the cover units where digit d+1 fits in base
+			// unit u; only lines with 2 to maxSize such places can be part
+			// of a fish.
+			var lines [MAX_SIZE]uint32
+			for idx, val := range p.cells {
+				if val == 0 && p.getPossibilities(idx/p.size, idx%p.size)&bit != 0 {
+					lines[p.unitOf(base, idx)] |= 1 << p.unitOf(cover, idx)
+				}
+			}
+			var usable []int
+			for u := 0; u < p.size; u++ {
+				if n := bits.OnesCount32(lines[u]); n >= 2 && n <= maxSize {
+					usable = append(usable, u)
+				}
+			}
+
+			for n := 2; n <= maxSize; n++ {
+				findFish(lines[:p.size], usable, n, 0, 0, 0, func(chosen, covered uint32) {
+					for c := covered; c != 0; c &= c - 1 {
+						for _, idx := range p.units[cover*p.size+bits.TrailingZeros32(c)] {
+							if chosen&(1<<p.unitOf(base, idx)) != 0 || p.cells[idx] != 0 {
+								continue
+							}
+							if p.getPossibilities(idx/p.size, idx%p.size)&bit != 0 {
+								p.eliminate(idx, bit)
+								changed = true
+							}
+						}
+					}
+				})
+			}
+		}
+	}
+	return changed
+}
+
+// findFish calls found for every set of n lines taken from usable, after
+// the first start of them, that together with the lines already in chosen
+// place the digit in exactly n cover units. covered is the union of the
+// places of the chosen lines.
+func findFish(lines []uint32, usable []int, n, start int, chosen, covered uint32, found func(chosen, covered uint32)) {
+	if bits.OnesCount32(chosen) == n {
+		if bits.OnesCount32(covered) == n {
+			found(chosen, covered)
+		}
+		return
+	}
+	for i := start; i < len(usable); i++ {
+		u := usable[i]
+		if c := covered | lines[u]; bits.OnesCount32(c) <= n {
+			findFish(lines, usable, n, i+1, chosen|1<<u, c, found)
+		}
+	}
+}
+
+// eliminateOutside removes the digits in subset from every cell of a unit
+// whose position is not in members, keeping masks in step. It reports
+// whether anything was removed.
+func (p *Puzzle) eliminateOutside(cells []int, masks []uint32, members uint32, subset uint32) bool {
+	changed := false
+	for i, idx := range cells {
+		if members&(1<<i) != 0 {
+			continue
+		}
+		if common := masks[i] & subset; common != 0 {
+			p.eliminate(idx, common)
+			masks[i] &^= common
+			changed = true
+		}
+	}
+	return changed
+}
+
+// eliminate rules out the candidates in mask for the cell at idx and
+// records it on the trail.
+func (p *Puzzle) eliminate(idx int, mask uint32) {
+	p.elim[idx] |= mask
+	p.trail = append(p.trail, move{idx: idx, elim: mask})
+}
+
+// place fills the cell at idx and records it on the trail.
+func (p *Puzzle) place(idx int, val byte, technique Technique) {
+	row, col := idx/p.size, idx%p.size
+	p.setCell(row, col, val)
+	p.trail = append(p.trail, move{idx: idx})
+	p.record(row, col, val, technique)
+}
+
+// undo reverts every change recorded since the trail had length mark.
+func (p *Puzzle) undo(mark int) {
+	for i := len(p.trail) - 1; i >= mark; i-- {
+		m := p.trail[i]
+		if m.elim != 0 {
+			p.elim[m.idx] &^= m.elim
+			continue
+		}
+		row, col, val := m.idx/p.size, m.idx%p.size, p.cells[m.idx]
+		p.clearCell(row, col, val)
+		p.record(row, col, val, Backtrack)
+	}
+	p.trail = p.trail[:mark]
+}
+
+// commit ends a successful search, dropping the trail and the eliminations
+// that only held within it.
+func (p *Puzzle) commit() {
+	p.trail = p.trail[:0]
+	clear(p.elim)
+}
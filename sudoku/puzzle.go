@@ -0,0 +1,528 @@
+// Package sudoku implements a bitmask-based backtracking Sudoku solver.
+package sudoku
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"math/rand"
+	"slices"
+)
+
+const (
+	SIZE      = 9
+	EMPTY     = '.'
+	ZERO      = '0'
+	SPACE     = ' '
+	GRID_SIZE = SIZE * SIZE
+	ALL_BITS  = 0x1FF
+	MAX_SIZE  = 25
+
+	// MIN_CLUES is the fewest givens a classic 9x9 puzzle can have and
+	// still have a unique solution.
+	MIN_CLUES = 17
+)
+
+// Candidate and unit masks are uint32 with bit d-1 standing for digit d, so
+// every supported size up to MAX_SIZE fits. This fails to compile if
+// MAX_SIZE outgrows the mask type.
+const _ = uint32(1<<MAX_SIZE - 1)
+
+// DIGITS holds the characters used for the values 1..N. Boards larger than
+// 9x9 continue with letters, so a 16x16 board uses 1-9 and A-G.
+const DIGITS = "123456789ABCDEFGHIJKLMNOP"
+
+var layouts = map[int]*layout{}
+
+// boxShapes lists the supported box geometries as rows by columns. Square
+// boxes give the 4x4 to 25x25 boards; 6x6 and 12x12 boards use 2x3 and 3x4
+// boxes.
+var boxShapes = [][2]int{{2, 2}, {2, 3}, {3, 3}, {3, 4}, {4, 4}, {5, 5}}
+
+func init() {
+	for _, shape := range boxShapes {
+		l := newLayout(shape[0], shape[1])
+		layouts[l.size] = l
+	}
+}
+
+// layout describes the geometry shared by every board of one size. A board
+// with Jigsaw regions gets a copy of its own.
+type layout struct {
+	size     int
+	boxRows  int // height of a box
+	boxCols  int // width of a box
+	numCells int
+	allBits  uint32
+	boxOf    []int
+	units    [][]int
+}
+
+func newLayout(boxRows, boxCols int) *layout {
+	size := boxRows * boxCols
+	l := &layout{
+		size:     size,
+		boxRows:  boxRows,
+		boxCols:  boxCols,
+		numCells: size * size,
+		allBits:  1<<size - 1,
+		boxOf:    make([]int, size*size),
+	}
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			l.boxOf[i*size+j] = (i/boxRows)*boxRows + j/boxCols
+		}
+	}
+	l.buildUnits()
+	return l
+}
+
+// buildUnits derives the unit lists from the size and boxOf.
+func (l *layout) buildUnits() {
+	size := l.size
+	rows := make([][]int, size)
+	cols := make([][]int, size)
+	boxes := make([][]int, size)
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			idx := i*size + j
+			rows[i] = append(rows[i], idx)
+			cols[j] = append(cols[j], idx)
+			boxes[l.boxOf[idx]] = append(boxes[l.boxOf[idx]], idx)
+		}
+	}
+	l.units = append(append(rows, cols...), boxes...)
+}
+
+// SizeFor returns the side length of the board whose grid holds n cells, or 0
+// if no supported board has that many cells.
+func SizeFor(n int) int {
+	for size, l := range layouts {
+		if l.numCells == n {
+			return size
+		}
+	}
+	return 0
+}
+
+type Puzzle struct {
+	*layout
+	cells     []byte
+	rows      []uint32
+	cols      []uint32
+	boxes     []uint32
+	emptyCell int
+
+	// free has bit idx%64 of word idx/64 set for every empty cell, so scans
+	// for empty cells can skip the filled ones.
+	free [(MAX_SIZE*MAX_SIZE + 63) / 64]uint64
+
+	// givens has the same layout as free, with a bit set for every cell
+	// that held a digit when the board was read.
+	givens [(MAX_SIZE*MAX_SIZE + 63) / 64]uint64
+
+	// extras holds the units added by variants such as SetDiagonal and
+	// SetWindoku, extraOf the indices of the extras holding each cell, and
+	// allUnits the layout's units followed by the extras. All are nil for
+	// a classic board.
+	extras   []extraUnit
+	extraOf  [][]int
+	allUnits [][]int
+
+	// cages holds the Killer Sudoku cages, if any, and cageOf the index of
+	// the cage each cell belongs to.
+	cages  []cageState
+	cageOf []int
+
+	// hiddenPairs and boxLine enable the hidden pair and box-line
+	// reduction eliminations in propagate.
+	hiddenPairs bool
+	boxLine     bool
+
+	// fish is the largest fish pattern propagate looks for, or below 2
+	// for none.
+	fish int
+
+	// maxNodes is the node limit set by SetNodeLimit, or 0 for none.
+	maxNodes int
+
+	// selector chooses the cell to branch on; nil means MinRemaining.
+	selector CellSelector
+
+	// rng, if set, randomizes the order in which candidates are tried.
+	rng *rand.Rand
+
+	// elim holds, per cell, the candidates ruled out by deduction during
+	// the current search on top of what the unit masks exclude.
+	elim []uint32
+
+	// allowed, if set, holds per cell the only candidates the input
+	// permits, as read by ParseMarked or narrowed by Forbid.
+	allowed []uint32
+
+	// trail records the cells placed and candidates eliminated by
+	// propagate so a failed branch can be rolled back.
+	trail []move
+
+	// history holds the edits made by Push, most recent last, for Undo.
+	history []edit
+
+	// steps collects the moves of a solve when recording is enabled by
+	// SolveWithSteps.
+	steps *[]Step
+}
+
+// NewPuzzle returns an empty board of the given side length: 4, 6, 9, 12,
+// 16 or 25.
+func NewPuzzle(size int) (*Puzzle, error) {
+	l, ok := layouts[size]
+	if !ok {
+		return nil, fmt.Errorf("sudoku: unsupported board size %d", size)
+	}
+	return newPuzzle(l), nil
+}
+
+func newPuzzle(l *layout) *Puzzle {
+	masks := make([]uint32, 3*l.size+l.numCells)
+	p := &Puzzle{
+		layout:    l,
+		cells:     make([]byte, l.numCells),
+		rows:      masks[:l.size],
+		cols:      masks[l.size : 2*l.size],
+		boxes:     masks[2*l.size : 3*l.size],
+		elim:      masks[3*l.size:],
+		emptyCell: l.numCells,
+	}
+	p.freeAll()
+	return p
+}
+
+// markGivens records every filled cell as a given.
+func (p *Puzzle) markGivens() {
+	p.givens = [len(p.givens)]uint64{}
+	for idx, val := range p.cells {
+		if val != 0 {
+			p.givens[idx/64] |= 1 << (idx % 64)
+		}
+	}
+}
+
+// given reports whether cell idx was a given.
+func (p *Puzzle) given(idx int) bool {
+	return p.givens[idx/64]&(1<<(idx%64)) != 0
+}
+
+// IsGiven reports whether the cell at (row, col) held a digit when the
+// board was parsed or loaded, as opposed to one placed later by Set, Push
+// or a solver. Out-of-range cells are not givens.
+func (p *Puzzle) IsGiven(row, col int) bool {
+	return p.checkCell(row, col) == nil && p.given(row*p.size+col)
+}
+
+// freeAll marks every cell empty in p.free.
+func (p *Puzzle) freeAll() {
+	p.free = [len(p.free)]uint64{}
+	for idx := 0; idx < p.numCells; idx++ {
+		p.free[idx/64] |= 1 << (idx % 64)
+	}
+}
+
+// Clone returns an independent copy of the board, including its masks and
+// variant settings, so the copy can be solved or edited without touching p.
+// Search scratch state such as the trail and step recording is not copied.
+func (p *Puzzle) Clone() *Puzzle {
+	c := newPuzzle(p.layout)
+	c.copyFrom(p)
+	return c
+}
+
+// copyFrom overwrites the board with the contents of q, which must have the
+// same layout.
+func (p *Puzzle) copyFrom(q *Puzzle) {
+	copy(p.cells, q.cells)
+	p.free = q.free
+	p.givens = q.givens
+	copy(p.rows, q.rows)
+	copy(p.cols, q.cols)
+	copy(p.boxes, q.boxes)
+	copy(p.elim, q.elim)
+	p.emptyCell = q.emptyCell
+	p.extras = slices.Clone(q.extras)
+	p.extraOf = q.extraOf
+	p.allUnits = q.allUnits
+	p.cages = append([]cageState(nil), q.cages...)
+	p.cageOf = q.cageOf
+	p.allowed = slices.Clone(q.allowed)
+	p.hiddenPairs = q.hiddenPairs
+	p.boxLine = q.boxLine
+	p.fish = q.fish
+	p.maxNodes = q.maxNodes
+	p.selector = q.selector
+	p.rng = q.rng
+	p.history = slices.Clone(q.history)
+}
+
+// ParsePuzzle reads a board written as one line of N*N characters, where '.',
+// '0' or a space marks an empty cell and the characters of DIGITS are
+// givens. The board size is inferred from the length, so 81 characters give
+// a classic 9x9 puzzle and 16, 36, 144, 256 or 625 give 4x4, 6x6, 12x12,
+// 16x16 or 25x25 boards.
+func ParsePuzzle(input string) (*Puzzle, error) {
+	p := &Puzzle{}
+	if err := p.Load(input); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Load replaces the board with the one in input, in the format read by
+// ParsePuzzle, reusing p's buffers when the size has not changed, so one
+// Puzzle can work through a batch without allocating a board per input.
+// Variant constraints are dropped, but solver settings such as
+// SetHiddenPairs and SetCellSelector are kept. The zero Puzzle is ready to
+// load into. On error the board is left partly filled.
+func (p *Puzzle) Load(input string) error {
+	size := SizeFor(len(input))
+	if size == 0 {
+		return fmt.Errorf("%w: puzzle has %d characters, not a supported board length", ErrInvalid, len(input))
+	}
+	if p.layout == nil || p.size != size {
+		hiddenPairs, boxLine, fish, maxNodes, selector, rng := p.hiddenPairs, p.boxLine, p.fish, p.maxNodes, p.selector, p.rng
+		*p = *newPuzzle(layouts[size])
+		p.hiddenPairs, p.boxLine, p.fish, p.maxNodes, p.selector, p.rng = hiddenPairs, boxLine, fish, maxNodes, selector, rng
+	} else {
+		p.Reset()
+	}
+
+	idx := 0
+	for i := 0; i < size; i++ {
+		for j := 0; j < size; j++ {
+			ch := input[idx]
+			if !isEmpty(ch) {
+				val := digitValue(ch)
+				if val == 0 || int(val) > size {
+					return fmt.Errorf("%w: invalid character %q at index %d", ErrInvalid, ch, idx)
+				}
+				p.setCell(i, j, val)
+			}
+			idx++
+		}
+	}
+	p.markGivens()
+	return nil
+}
+
+// Reset empties the board in place, keeping its size and buffers, and drops
+// its variant constraints and Push history. Solver settings are kept, as
+// with Load.
+func (p *Puzzle) Reset() {
+	if p.layout == nil {
+		return
+	}
+	p.layout = layouts[p.size]
+	clear(p.cells)
+	p.freeAll()
+	p.givens = [len(p.givens)]uint64{}
+	clear(p.rows)
+	clear(p.cols)
+	clear(p.boxes)
+	clear(p.elim)
+	p.emptyCell = p.numCells
+	p.extras, p.extraOf, p.allUnits = nil, nil, nil
+	p.cages, p.cageOf = nil, nil
+	p.allowed = nil
+	p.trail = p.trail[:0]
+	p.history = p.history[:0]
+	p.steps = nil
+}
+
+func isEmpty(ch byte) bool {
+	return ch == EMPTY || ch == ZERO || ch == SPACE
+}
+
+// digitValue decodes a character of DIGITS, ignoring letter case. It returns
+// 0 for any other character.
+func digitValue(ch byte) byte {
+	switch {
+	case ch >= '1' && ch <= '9':
+		return ch - '0'
+	case ch >= 'A' && ch <= 'Z':
+		return ch - 'A' + 10
+	case ch >= 'a' && ch <= 'z':
+		return ch - 'a' + 10
+	}
+	return 0
+}
+
+// ClueCount returns the number of filled cells.
+func (p *Puzzle) ClueCount() int {
+	return p.numCells - p.emptyCell
+}
+
+// EmptyCount returns the number of cells still to be filled.
+func (p *Puzzle) EmptyCount() int {
+	return p.emptyCell
+}
+
+// EmptyCells returns the (row, col) of every empty cell, counted from 0, in
+// row-major order.
+func (p *Puzzle) EmptyCells() [][2]int {
+	cells := make([][2]int, 0, p.emptyCell)
+	for w, word := range p.free[:(p.numCells+63)/64] {
+		for ; word != 0; word &= word - 1 {
+			idx := w*64 + bits.TrailingZeros64(word)
+			cells = append(cells, [2]int{idx / p.size, idx % p.size})
+		}
+	}
+	return cells
+}
+
+// Hash returns a 64-bit FNV-1a fingerprint of the cells, so boards can be
+// grouped, for example by solution, without keeping their strings. Equal
+// boards of the same size hash alike; different ones collide only by
+// chance. Variant constraints are not included.
+func (p *Puzzle) Hash() uint64 {
+	h := fnv.New64a()
+	h.Write([]byte{byte(p.size)})
+	h.Write(p.cells)
+	return h.Sum64()
+}
+
+// Size returns the side length of the board.
+func (p *Puzzle) Size() int {
+	return p.size
+}
+
+func (p *Puzzle) getBox(row, col int) int {
+	return p.boxOf[row*p.size+col]
+}
+
+// unitList returns the groups of cells that must each hold every digit once.
+func (p *Puzzle) unitList() [][]int {
+	if p.allUnits != nil {
+		return p.allUnits
+	}
+	return p.units
+}
+
+func (p *Puzzle) getPossibilities(row, col int) uint32 {
+	box := p.getBox(row, col)
+	used := p.rows[row] | p.cols[col] | p.boxes[box]
+	idx := row*p.size + col
+	if p.extraOf != nil {
+		for _, n := range p.extraOf[idx] {
+			used |= p.extras[n].used
+		}
+	}
+	poss := ^used & p.allBits &^ p.elim[idx]
+	if p.allowed != nil {
+		poss &= p.allowed[idx]
+	}
+	if p.cageOf != nil {
+		poss = p.cagePossibilities(idx, poss)
+	}
+	return poss
+}
+
+func (p *Puzzle) setCell(row, col int, val byte) {
+	idx := row*p.size + col
+	p.cells[idx] = val
+	p.free[idx/64] &^= 1 << (idx % 64)
+	bit := uint32(1) << (val - 1)
+	p.rows[row] |= bit
+	p.cols[col] |= bit
+	p.boxes[p.boxOf[idx]] |= bit
+	if p.extraOf != nil {
+		for _, n := range p.extraOf[idx] {
+			p.extras[n].used |= bit
+		}
+	}
+	if p.cageOf != nil {
+		c := &p.cages[p.cageOf[idx]]
+		c.used |= bit
+		c.total += int(val)
+		c.empty--
+	}
+	p.emptyCell--
+}
+
+func (p *Puzzle) clearCell(row, col int, val byte) {
+	idx := row*p.size + col
+	p.cells[idx] = 0
+	p.free[idx/64] |= 1 << (idx % 64)
+	bit := ^(uint32(1) << (val - 1))
+	p.rows[row] &= bit
+	p.cols[col] &= bit
+	p.boxes[p.boxOf[idx]] &= bit
+	if p.extraOf != nil {
+		for _, n := range p.extraOf[idx] {
+			p.extras[n].used &= bit
+		}
+	}
+	if p.cageOf != nil {
+		c := &p.cages[p.cageOf[idx]]
+		c.used &= bit
+		c.total -= int(val)
+		c.empty++
+	}
+	p.emptyCell++
+}
+
+// ToString serializes the grid as a flat line of N*N characters, using '.'
+// for empty cells, the form read by ParsePuzzle. String gives the
+// multi-line grid instead.
+func (p *Puzzle) ToString() string {
+	result := make([]byte, p.numCells)
+	for idx, val := range p.cells {
+		if val == 0 {
+			result[idx] = EMPTY
+		} else {
+			result[idx] = DIGITS[val-1]
+		}
+	}
+	return string(result)
+}
+
+// Validate reports the first pair of givens that share a digit within a row,
+// column or box, or within an extra unit such as a diagonal when a variant
+// adds them. Rows, columns and boxes are numbered from 1 in the error.
+func (p *Puzzle) Validate() error {
+	var rows, cols, boxes [MAX_SIZE]uint32
+	for i := 0; i < p.size; i++ {
+		for j := 0; j < p.size; j++ {
+			val := p.cells[i*p.size+j]
+			if val == 0 {
+				continue
+			}
+			bit := uint32(1) << (val - 1)
+			box := p.getBox(i, j)
+			switch {
+			case rows[i]&bit != 0:
+				return fmt.Errorf("%w: digit %c repeated in row %d", ErrInvalid, DIGITS[val-1], i+1)
+			case cols[j]&bit != 0:
+				return fmt.Errorf("%w: digit %c repeated in column %d", ErrInvalid, DIGITS[val-1], j+1)
+			case boxes[box]&bit != 0:
+				return fmt.Errorf("%w: digit %c repeated in box %d", ErrInvalid, DIGITS[val-1], box+1)
+			}
+			rows[i] |= bit
+			cols[j] |= bit
+			boxes[box] |= bit
+		}
+	}
+
+	for _, u := range p.extras {
+		var used uint32
+		for _, idx := range u.cells {
+			val := p.cells[idx]
+			if val == 0 {
+				continue
+			}
+			bit := uint32(1) << (val - 1)
+			if used&bit != 0 {
+				return fmt.Errorf("%w: digit %c repeated in %s", ErrInvalid, DIGITS[val-1], u.name)
+			}
+			used |= bit
+		}
+	}
+	return nil
+}
@@ -0,0 +1,372 @@
+package sudoku
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCloneIsIndependent(t *testing.T) {
+	input := benchPuzzles[1].puzzle
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := ParsePuzzle(input)
+
+	c := p.Clone()
+	if !reflect.DeepEqual(c, p) {
+		t.Fatalf("clone %s differs from original %s", c.ToString(), p.ToString())
+	}
+	if !c.Solve() {
+		t.Fatal("clone has no solution")
+	}
+
+	if !reflect.DeepEqual(p, want) {
+		t.Errorf("solving the clone changed the original to %s", p.ToString())
+	}
+}
+
+func TestParseRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string // "" means the input itself
+	}{
+		{"empty 4x4", strings.Repeat(".", 16), ""},
+		{"full 4x4", "1234341221434321", ""},
+		{"zeros", strings.ReplaceAll(benchPuzzles[0].puzzle, ".", "0"), benchPuzzles[0].puzzle},
+		{"spaces", strings.ReplaceAll(benchPuzzles[0].puzzle, ".", " "), benchPuzzles[0].puzzle},
+		{"lowercase", "a" + strings.Repeat(".", 143), "A" + strings.Repeat(".", 143)},
+		{"16x16", "123456789ABCDEFG" + strings.Repeat(".", 240), ""},
+	}
+	for _, bp := range benchPuzzles {
+		tests = append(tests, struct{ name, input, want string }{bp.name, bp.puzzle, ""})
+	}
+	for _, tt := range tests {
+		want := tt.want
+		if want == "" {
+			want = tt.input
+		}
+		p, err := ParsePuzzle(tt.input)
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if got := p.ToString(); got != want {
+			t.Errorf("%s: ParsePuzzle(%q).ToString() = %q, want %q", tt.name, tt.input, got, want)
+		}
+	}
+}
+
+func TestBinaryRoundTrip(t *testing.T) {
+	inputs := []string{"1234341221434321", "123456789ABCDEFG" + strings.Repeat(".", 240)}
+	for _, bp := range benchPuzzles {
+		inputs = append(inputs, bp.puzzle)
+	}
+	for _, input := range inputs {
+		p, err := ParsePuzzle(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := p.MarshalBinary()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var q Puzzle
+		if err := q.UnmarshalBinary(data); err != nil {
+			t.Fatalf("UnmarshalBinary(%x): %v", data, err)
+		}
+		if !reflect.DeepEqual(&q, p) {
+			t.Errorf("%s came back as %s from %d bytes", input, q.ToString(), len(data))
+		}
+	}
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	inputs := []string{"12..3.....4....1", "123456" + strings.Repeat(".", 30), "123456789ABCDEFG" + strings.Repeat(".", 240)}
+	for _, bp := range benchPuzzles {
+		inputs = append(inputs, bp.puzzle)
+	}
+	for _, input := range inputs {
+		p, err := ParsePuzzle(input)
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, err := json.Marshal(p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var q Puzzle
+		if err := json.Unmarshal(data, &q); err != nil {
+			t.Fatalf("Unmarshal(%s): %v", data, err)
+		}
+		if !reflect.DeepEqual(&q, p) {
+			t.Errorf("%s came back as %s from %s", input, q.ToString(), data)
+		}
+		for idx := range input {
+			row, col := idx/q.size, idx%q.size
+			if got, want := q.IsGiven(row, col), input[idx] != EMPTY; got != want {
+				t.Errorf("%s: IsGiven(%d, %d) = %v after Unmarshal, want %v", input, row, col, got, want)
+			}
+		}
+	}
+
+	p, err := ParsePuzzle("12..3.....4....1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.Marshal(p)
+	if want := "[[1,2,0,0],[3,0,0,0],[0,0,4,0],[0,0,0,1]]"; err != nil || string(data) != want {
+		t.Errorf("Marshal = %s, %v, want %s", data, err, want)
+	}
+	for _, bad := range []string{`[[1,2],[3,4]]`, `[[1,2,0,0],[3,0,0],[0,0,4,0],[0,0,0,1]]`, `[[5,0,0,0],[0,0,0,0],[0,0,0,0],[0,0,0,0]]`} {
+		var q Puzzle
+		if err := json.Unmarshal([]byte(bad), &q); !errors.Is(err, ErrInvalid) {
+			t.Errorf("Unmarshal(%s) = %v, want ErrInvalid", bad, err)
+		}
+	}
+}
+
+func TestArrayRoundTrip(t *testing.T) {
+	p, err := ParsePuzzle(benchPuzzles[0].puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	grid, err := p.ToArray()
+	if err != nil {
+		t.Fatal(err)
+	}
+	q, err := FromArray(grid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := q.ToString(); got != benchPuzzles[0].puzzle {
+		t.Errorf("FromArray(ToArray()) = %s, want %s", got, benchPuzzles[0].puzzle)
+	}
+
+	for _, size := range []int{4, 6, 16} {
+		other, err := NewPuzzle(size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := other.ToArray(); err == nil {
+			t.Errorf("%dx%d ToArray gave no error", size, size)
+		}
+	}
+}
+
+func FuzzParsePuzzle(f *testing.F) {
+	for _, bp := range benchPuzzles {
+		f.Add(bp.puzzle)
+	}
+	f.Add("")
+	f.Add("1234341221434321")
+	f.Add(strings.Repeat("0", 80) + "A")
+
+	f.Fuzz(func(t *testing.T, input string) {
+		p, err := ParsePuzzle(input)
+		if err != nil {
+			if p != nil {
+				t.Fatalf("ParsePuzzle(%q) returned a puzzle with error %v", input, err)
+			}
+			if !errors.Is(err, ErrInvalid) {
+				t.Fatalf("ParsePuzzle(%q) error %v does not wrap ErrInvalid", input, err)
+			}
+			return
+		}
+		if SizeFor(len(input)) == 0 {
+			t.Fatalf("ParsePuzzle accepted %d characters", len(input))
+		}
+		want := strings.NewReplacer(string(ZERO), string(EMPTY), string(SPACE), string(EMPTY)).Replace(strings.ToUpper(input))
+		if got := p.ToString(); got != want {
+			t.Fatalf("ParsePuzzle(%q).ToString() = %q, want %q", input, got, want)
+		}
+	})
+}
+
+func TestBoxGeometry(t *testing.T) {
+	tests := []struct {
+		size, boxRows, boxCols int
+	}{
+		{6, 2, 3},
+		{9, 3, 3},
+		{12, 3, 4},
+	}
+	for _, tt := range tests {
+		p, err := NewPuzzle(tt.size)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := SizeFor(tt.size * tt.size); got != tt.size {
+			t.Errorf("SizeFor(%d) = %d, want %d", tt.size*tt.size, got, tt.size)
+		}
+		if p.getBox(tt.boxRows-1, tt.boxCols-1) != 0 || p.getBox(0, tt.boxCols) != 1 || p.getBox(tt.boxRows, 0) != tt.boxRows {
+			t.Errorf("%dx%d: boxes are not %dx%d", tt.size, tt.size, tt.boxRows, tt.boxCols)
+		}
+
+		if !p.Solve() || !p.IsSolvedCorrectly() {
+			t.Fatalf("%dx%d: empty board not solved correctly: %s", tt.size, tt.size, p.ToString())
+		}
+
+		// Clear every other cell and solve again from the flat form.
+		b := []byte(p.ToString())
+		for i := 0; i < len(b); i += 2 {
+			b[i] = EMPTY
+		}
+		givens, err := ParsePuzzle(string(b))
+		if err != nil {
+			t.Fatal(err)
+		}
+		solved, ok := givens.Solution()
+		if !ok || !solved.CheckAgainst(givens) {
+			t.Errorf("%dx%d: %s not solved correctly", tt.size, tt.size, b)
+		}
+	}
+}
+
+func TestPrettyRectangularBoxes(t *testing.T) {
+	p, err := ParsePuzzle("123456" + strings.Repeat(".", 30))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(p.Pretty(), "\n")
+	want := []string{
+		"+-------+-------+",
+		"| 1 2 3 | 4 5 6 |",
+		"| . . . | . . . |",
+		"+-------+-------+",
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("Pretty line %d = %q, want %q", i+1, lines[i], line)
+		}
+	}
+}
+
+func TestPencilGrid(t *testing.T) {
+	p, err := ParsePuzzle("12.." + strings.Repeat(".", 12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(p.PencilGrid(), "\n")
+	want := []string{
+		"+-----------+-----------+",
+		"| 1 1   2 2 |           |",
+		"| 1 1   2 2 | 3 4   3 4 |",
+		"|           |           |",
+		"|           | 1 2   1 2 |",
+		"| 3 4   3 4 | 3 4   3 4 |",
+		"+-----------+-----------+",
+	}
+	for i, line := range want {
+		if lines[i] != line {
+			t.Errorf("PencilGrid line %d = %q, want %q", i+1, lines[i], line)
+		}
+	}
+}
+
+func TestLargestBoard(t *testing.T) {
+	p, err := NewPuzzle(MAX_SIZE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Solve() || !p.IsSolvedCorrectly() {
+		t.Fatalf("empty %dx%d board not solved correctly: %s", MAX_SIZE, MAX_SIZE, p.ToString())
+	}
+
+	// Clear a third of the cells, including every cell holding the digits
+	// above 16 that a 16-bit mask would lose.
+	b := []byte(p.ToString())
+	for i := range b {
+		if i%3 == 0 || digitValue(b[i]) > 16 {
+			b[i] = EMPTY
+		}
+	}
+	givens, err := ParsePuzzle(string(b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	solved, ok := givens.Solution()
+	if !ok || !solved.CheckAgainst(givens) {
+		t.Errorf("%dx%d puzzle not solved correctly", MAX_SIZE, MAX_SIZE)
+	}
+}
+
+func TestEmptyCount(t *testing.T) {
+	input := benchPuzzles[1].puzzle
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.EmptyCount(), strings.Count(input, "."); got != want {
+		t.Fatalf("EmptyCount() = %d, want %d", got, want)
+	}
+	cells := p.EmptyCells()
+	if len(cells) != p.EmptyCount() {
+		t.Fatalf("EmptyCells() has %d cells, want %d", len(cells), p.EmptyCount())
+	}
+	for _, cell := range cells {
+		if input[cell[0]*SIZE+cell[1]] != EMPTY {
+			t.Errorf("EmptyCells() includes filled cell %v", cell)
+		}
+	}
+	if !p.Solve() {
+		t.Fatal("no solution")
+	}
+	if got := p.EmptyCount(); got != 0 {
+		t.Errorf("EmptyCount() after Solve = %d, want 0", got)
+	}
+}
+
+func TestIsGiven(t *testing.T) {
+	input := benchPuzzles[1].puzzle
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !p.Solve() {
+		t.Fatal("no solution")
+	}
+	for idx := range input {
+		row, col := idx/SIZE, idx%SIZE
+		if got, want := p.IsGiven(row, col), input[idx] != EMPTY; got != want {
+			t.Errorf("IsGiven(%d, %d) = %v, want %v", row, col, got, want)
+		}
+	}
+	if p.IsGiven(SIZE, 0) {
+		t.Error("IsGiven outside the board = true")
+	}
+}
+
+func TestNodeLimit(t *testing.T) {
+	input := benchPuzzles[2].puzzle
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stats, ok := p.Clone().SolveStats()
+	if !ok {
+		t.Fatal("no solution")
+	}
+
+	p.SetNodeLimit(stats.Nodes - 1)
+	if err := p.SolveE(); !errors.Is(err, ErrNodeLimit) {
+		t.Fatalf("SolveE with %d nodes = %v, want ErrNodeLimit", stats.Nodes-1, err)
+	}
+	if got := p.ToString(); got != input {
+		t.Fatalf("aborted search left %s", got)
+	}
+
+	// The count starts afresh for each solve, so a limit that fits one
+	// solve fits every repeat of it.
+	p.SetNodeLimit(stats.Nodes)
+	for i := 0; i < 2; i++ {
+		q := p.Clone()
+		if err := q.SolveE(); err != nil {
+			t.Fatalf("solve %d with %d nodes: %v", i+1, stats.Nodes, err)
+		}
+	}
+}
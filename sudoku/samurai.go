@@ -0,0 +1,184 @@
+package sudoku
+
+import (
+	"fmt"
+	"math/bits"
+	"strings"
+)
+
+// samuraiSize is the width and height of a Samurai board: five 9x9 grids,
+// four in the corners and one in the middle overlapping a box of each.
+const samuraiSize = 21
+
+// samuraiOrigins holds the top-left board coordinate of each grid, in the
+// order top-left, top-right, centre, bottom-left, bottom-right.
+var samuraiOrigins = [5][2]int{{0, 0}, {0, 12}, {6, 6}, {12, 0}, {12, 12}}
+
+// samuraiCell locates one board cell within one of the grids.
+type samuraiCell struct {
+	grid, row, col int
+}
+
+// samuraiOwners lists, for every board cell in row-major order, the grids
+// that contain it: none in the gaps, two in the shared corner boxes and one
+// everywhere else.
+var samuraiOwners [samuraiSize * samuraiSize][]samuraiCell
+
+func init() {
+	for g, origin := range samuraiOrigins {
+		for i := 0; i < SIZE; i++ {
+			for j := 0; j < SIZE; j++ {
+				idx := (origin[0]+i)*samuraiSize + origin[1] + j
+				samuraiOwners[idx] = append(samuraiOwners[idx], samuraiCell{g, i, j})
+			}
+		}
+	}
+}
+
+// Samurai is a Samurai Sudoku: five classic grids where the centre grid
+// shares each of its corner boxes with one of the other four. Each grid
+// must be a valid solution on its own, and a shared cell holds the same
+// digit in both of its grids.
+type Samurai struct {
+	grids [5]*Puzzle
+}
+
+// NewSamurai returns an empty Samurai board.
+func NewSamurai() *Samurai {
+	s := &Samurai{}
+	for g := range s.grids {
+		s.grids[g] = newPuzzle(layouts[SIZE])
+	}
+	return s
+}
+
+// ParseSamurai reads a board written as 21 lines of 21 characters, using
+// '.' or '0' for empty cells like ParsePuzzle. Characters in the gaps
+// between the corner grids are ignored, so they may be left as spaces and
+// lines may stop short of the last grid. Blank lines are skipped.
+func ParseSamurai(input string) (*Samurai, error) {
+	var lines []string
+	for _, line := range strings.Split(input, "\n") {
+		line = strings.TrimRight(line, " \t\r")
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) != samuraiSize {
+		return nil, fmt.Errorf("%w: samurai board has %d lines, want %d", ErrInvalid, len(lines), samuraiSize)
+	}
+
+	s := NewSamurai()
+	for i, line := range lines {
+		if len(line) > samuraiSize {
+			return nil, fmt.Errorf("%w: samurai line %d has %d characters, want at most %d", ErrInvalid, i+1, len(line), samuraiSize)
+		}
+		for j := 0; j < len(line); j++ {
+			owners := samuraiOwners[i*samuraiSize+j]
+			ch := line[j]
+			if len(owners) == 0 || isEmpty(ch) {
+				continue
+			}
+			val := digitValue(ch)
+			if val == 0 || int(val) > SIZE {
+				return nil, fmt.Errorf("%w: invalid character %q at line %d, column %d", ErrInvalid, ch, i+1, j+1)
+			}
+			for _, c := range owners {
+				s.grids[c.grid].setCell(c.row, c.col, val)
+			}
+		}
+	}
+	for _, p := range s.grids {
+		p.markGivens()
+	}
+	return s, nil
+}
+
+// Grid returns a copy of grid g, numbered 0 to 4 as top-left, top-right,
+// centre, bottom-left and bottom-right.
+func (s *Samurai) Grid(g int) *Puzzle {
+	return s.grids[g].Clone()
+}
+
+// Validate reports the first rule broken by the givens of any grid.
+func (s *Samurai) Validate() error {
+	for g, p := range s.grids {
+		if err := p.Validate(); err != nil {
+			return fmt.Errorf("grid %d: %w", g+1, err)
+		}
+	}
+	return nil
+}
+
+// Solve fills the board in place and reports whether a solution was found.
+func (s *Samurai) Solve() bool {
+	return s.search()
+}
+
+// search fills the board cell by cell, always branching on the empty cell
+// with the fewest candidates across all of the grids that contain it.
+func (s *Samurai) search() bool {
+	best := -1
+	var bestPoss uint32
+	minCount := SIZE + 1
+	for idx, owners := range samuraiOwners {
+		if len(owners) == 0 {
+			continue
+		}
+		first := owners[0]
+		if s.grids[first.grid].cells[first.row*SIZE+first.col] != 0 {
+			continue
+		}
+		poss := uint32(ALL_BITS)
+		for _, c := range owners {
+			poss &= s.grids[c.grid].getPossibilities(c.row, c.col)
+		}
+		if count := bits.OnesCount32(poss); count < minCount {
+			if count == 0 {
+				return false
+			}
+			best, bestPoss, minCount = idx, poss, count
+		}
+	}
+	if best < 0 {
+		return true
+	}
+
+	owners := samuraiOwners[best]
+	for poss := bestPoss; poss != 0; poss &= poss - 1 {
+		val := byte(bits.TrailingZeros32(poss) + 1)
+		for _, c := range owners {
+			s.grids[c.grid].setCell(c.row, c.col, val)
+		}
+		if s.search() {
+			return true
+		}
+		for _, c := range owners {
+			s.grids[c.grid].clearCell(c.row, c.col, val)
+		}
+	}
+	return false
+}
+
+// String renders the board in the 21-line layout read by ParseSamurai, with
+// '.' for empty cells and spaces in the gaps.
+func (s *Samurai) String() string {
+	var sb strings.Builder
+	sb.Grow(samuraiSize * (samuraiSize + 1))
+	for i := 0; i < samuraiSize; i++ {
+		line := make([]byte, samuraiSize)
+		for j := range line {
+			line[j] = ' '
+			if owners := samuraiOwners[i*samuraiSize+j]; len(owners) > 0 {
+				c := owners[0]
+				line[j] = EMPTY
+				if val := s.grids[c.grid].cells[c.row*SIZE+c.col]; val != 0 {
+					line[j] = DIGITS[val-1]
+				}
+			}
+		}
+		sb.WriteString(strings.TrimRight(string(line), " "))
+		sb.WriteByte('\n')
+	}
+	return sb.String()
+}
@@ -0,0 +1,77 @@
+package sudoku
+
+import (
+	"strings"
+	"testing"
+)
+
+var samuraiPuzzle = strings.Join([]string{
+	"1..4..7..   3.1..6..9",
+	".5..8..2.   ...5..1..",
+	"7.9..3..6   .8..3..5.",
+	"...6..8..   4.3..2..8",
+	".7..1..6.   .2.9..3..",
+	"6.4..8..7   .9..1..7.",
+	".1.2..9..1.3..7..1...",
+	".4..9..3.5..2..3.5..7",
+	"..8....7..6..3.6..8..",
+	"      1.6..4..3",
+	"      ..7.8..1.",
+	"      .8.3..7..",
+	"3.6..9..5..7.8..2..6.",
+	"5..1..7....1..2..9.4.",
+	".2..5.8..2..6....8..9",
+	"..1.3..7.   .2.8..7..",
+	"4..6.5..8   ..7.1..8.",
+	".9.2..3..   8..2.7..5",
+	"..2.4..5.   .4....9..",
+	"7..9.1..2   ..8.9..2.",
+	".3....6..   7..3.2..1",
+}, "\n")
+
+func TestSamuraiSolve(t *testing.T) {
+	s, err := ParseSamurai(samuraiPuzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if !s.Solve() {
+		t.Fatal("no solution")
+	}
+
+	solved := strings.Split(s.String(), "\n")
+	for i, line := range strings.Split(samuraiPuzzle, "\n") {
+		for j := 0; j < len(line); j++ {
+			if line[j] != EMPTY && line[j] != ' ' && solved[i][j] != line[j] {
+				t.Errorf("line %d, column %d: given %c became %c", i+1, j+1, line[j], solved[i][j])
+			}
+		}
+	}
+
+	var grids [5]*Puzzle
+	for g := range grids {
+		grids[g] = s.Grid(g)
+		if grids[g].EmptyCount() != 0 || grids[g].Validate() != nil {
+			t.Errorf("grid %d is incomplete or breaks the rules:\n%s", g+1, grids[g])
+		}
+	}
+	// Box k of the centre grid is the opposite corner box of grid k.
+	centre := grids[2]
+	for k, g := range []int{0, 1, 3, 4} {
+		cr, cc := 6*(k/2), 6*(k%2)
+		for i := 0; i < 3; i++ {
+			for j := 0; j < 3; j++ {
+				row, col := 6-cr+i, 6-cc+j
+				want, _ := grids[g].At(row, col)
+				if got, _ := centre.At(cr+i, cc+j); got != want {
+					t.Errorf("centre (%d, %d) = %d, grid %d has %d", cr+i, cc+j, got, g+1, want)
+				}
+				if grids[g].IsGiven(row, col) != centre.IsGiven(cr+i, cc+j) {
+					t.Errorf("centre (%d, %d) and grid %d disagree on whether it is a given", cr+i, cc+j, g+1)
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package sudoku
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// decoration holds the characters ParseGrid strips from grid rows.
+const decoration = "|+-*"
+
+// comment starts a metadata line in .sdk files and batch input.
+const comment = "#"
+
+// headers are the prefixes of batch input lines that introduce a puzzle
+// rather than hold one, such as "Grid 01" in Project Euler files.
+var headers = []string{comment, "%", "Grid"}
+
+// headerLabel reports whether line is a header and returns its text with
+// any comment marker removed.
+func headerLabel(line string) (string, bool) {
+	for _, h := range headers {
+		if strings.HasPrefix(line, h) {
+			if h != "Grid" {
+				line = line[len(h):]
+			}
+			return strings.TrimSpace(line), true
+		}
+	}
+	return "", false
+}
+
+// ParseGrid reads a board written one row per line, as in
+//
+//	53..7....
+//	6..195...
+//	...
+//
+// Box-drawing decoration made of '|', '+', '-' and '*' is ignored, and lines
+// left empty by that are dropped, so Simple Sudoku (.ss) files parse as is.
+// The board size is taken from the number of rows.
+func ParseGrid(lines []string) (*Puzzle, error) {
+	rows := gridRows(lines)
+	if _, ok := layouts[len(rows)]; !ok {
+		return nil, fmt.Errorf("%w: grid has %d rows, not a supported board size", ErrInvalid, len(rows))
+	}
+	for i, row := range rows {
+		if len(row) != len(rows) {
+			return nil, fmt.Errorf("%w: grid row %d has %d cells, want %d", ErrInvalid, i+1, len(row), len(rows))
+		}
+	}
+	return ParsePuzzle(strings.Join(rows, ""))
+}
+
+// ParseGrids reads boards stacked one after another with no separator, as
+// in files that hold each 9x9 puzzle as nine lines of nine. The board size
+// is taken from the width of the first row, decoration is ignored as in
+// ParseGrid, and every row must have that width.
+func ParseGrids(lines []string) ([]*Puzzle, error) {
+	rows := gridRows(lines)
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	size := len(rows[0])
+	if _, ok := layouts[size]; !ok {
+		return nil, fmt.Errorf("%w: grid rows have %d cells, not a supported board size", ErrInvalid, size)
+	}
+	for i, row := range rows {
+		if len(row) != size {
+			return nil, fmt.Errorf("%w: grid row %d has %d cells, want %d", ErrInvalid, i+1, len(row), size)
+		}
+	}
+	if len(rows)%size != 0 {
+		return nil, fmt.Errorf("%w: %d rows do not make whole %dx%d grids", ErrInvalid, len(rows), size, size)
+	}
+
+	var puzzles []*Puzzle
+	for start := 0; start < len(rows); start += size {
+		p, err := ParsePuzzle(strings.Join(rows[start:start+size], ""))
+		if err != nil {
+			return nil, fmt.Errorf("grid %d: %w", start/size+1, err)
+		}
+		puzzles = append(puzzles, p)
+	}
+	return puzzles, nil
+}
+
+// gridRows strips the decoration from lines and drops those left empty.
+func gridRows(lines []string) []string {
+	var rows []string
+	for _, line := range lines {
+		if row := stripDecoration(line); row != "" {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+func stripDecoration(line string) string {
+	return strings.Map(func(r rune) rune {
+		if strings.ContainsRune(decoration, r) {
+			return -1
+		}
+		return r
+	}, line)
+}
+
+// trimRow drops the '\r' of CRLF input and any spaces around a grid row.
+// Spaces also mark empty cells, so a line that is already a row of a
+// supported width keeps them. A line of nothing but spaces is blank.
+func trimRow(line string) string {
+	line = strings.TrimRight(line, "\r")
+	if strings.TrimSpace(line) == "" {
+		return ""
+	}
+	if _, ok := layouts[len(stripDecoration(line))]; ok {
+		return line
+	}
+	return strings.TrimSpace(line)
+}
+
+// ParseSDK reads a puzzle in the SadMan Software .sdk format: optional
+// metadata lines starting with '#' followed by one row per line, with '.',
+// '0' or a space for empty cells.
+func ParseSDK(input string) (*Puzzle, error) {
+	var rows []string
+	for _, line := range strings.Split(input, "\n") {
+		line = trimRow(line)
+		if line != "" && !strings.HasPrefix(line, comment) {
+			rows = append(rows, line)
+		}
+	}
+	return ParseGrid(rows)
+}
+
+// Scanner reads puzzles from a stream that mixes one-line puzzles with
+// multi-line grid blocks. Grid blocks are separated from what follows by a
+// blank line, a one-line puzzle or a header line, though a block may also
+// hold several grids stacked with no separator, as ParseGrids reads. Header
+// lines start with '#', '%' or "Grid"; they are not puzzles, but the last
+// one before a puzzle becomes its label.
+type Scanner struct {
+	// OnSkip, if set, is called for input that is not a puzzle, with the
+	// line number it starts on.
+	OnSkip func(line int, err error)
+
+	sc      *bufio.Scanner
+	lineNo  int
+	block   []string
+	start   int
+	pending []scanned
+	text    string
+	line    int
+	label   string
+	header  string
+}
+
+type scanned struct {
+	text  string
+	line  int
+	label string
+}
+
+// NewScanner returns a Scanner reading from r.
+func NewScanner(r io.Reader) *Scanner {
+	return &Scanner{sc: bufio.NewScanner(r)}
+}
+
+// Scan advances to the next puzzle, returning false at the end of the input.
+func (s *Scanner) Scan() bool {
+	for len(s.pending) == 0 {
+		if !s.sc.Scan() {
+			s.flush()
+			if len(s.pending) == 0 {
+				return false
+			}
+			break
+		}
+		s.lineNo++
+		// Trimming drops the '\r' of CRLF files along with stray spaces
+		// that would otherwise change a line's length. Spaces also mark
+		// empty cells, so a line whose untrimmed length is a puzzle's or
+		// a grid row's is kept whole.
+		raw := strings.TrimRight(s.sc.Text(), "\r")
+		line := trimRow(raw)
+		if !isFlatLength(len(line)) && isFlatLength(len(raw)) {
+			line = raw
+		}
+
+		if label, ok := headerLabel(line); ok {
+			s.flush()
+			s.header = label
+			continue
+		}
+		switch {
+		case isFlatLength(len(line)):
+			s.flush()
+			s.emit(line, s.lineNo)
+		case line == "":
+			s.flush()
+		default:
+			if len(s.block) == 0 {
+				s.start = s.lineNo
+			}
+			s.block = append(s.block, line)
+		}
+	}
+
+	next := s.pending[0]
+	s.text, s.line, s.label = next.text, next.line, next.label
+	s.pending = s.pending[1:]
+	return true
+}
+
+// Text returns the current puzzle as a one-line string.
+func (s *Scanner) Text() string {
+	return s.text
+}
+
+// Label returns the text of the header line before the current puzzle,
+// without its comment marker, or "" if it had none.
+func (s *Scanner) Label() string {
+	return s.label
+}
+
+// Line returns the line number the current puzzle starts on.
+func (s *Scanner) Line() int {
+	return s.line
+}
+
+// Err returns the first read error encountered by the Scanner.
+func (s *Scanner) Err() error {
+	return s.sc.Err()
+}
+
+// isFlatLength reports whether a line of n characters can only be a one-line
+// puzzle. A 16-character line may also be a row of a 16x16 grid.
+func isFlatLength(n int) bool {
+	return n > MAX_SIZE && SizeFor(n) != 0
+}
+
+// emit queues a puzzle, giving it the pending header as its label.
+func (s *Scanner) emit(text string, line int) {
+	s.pending = append(s.pending, scanned{text, line, s.header})
+	s.header = ""
+}
+
+// flush turns the open block, if any, into pending puzzles.
+func (s *Scanner) flush() {
+	block, start := s.block, s.start
+	s.block = nil
+	if len(block) == 0 {
+		return
+	}
+
+	if len(block) == 1 && SizeFor(len(block[0])) != 0 {
+		s.emit(block[0], start)
+		return
+	}
+	p, err := ParseGrid(block)
+	if err == nil {
+		s.emit(p.ToString(), start)
+		return
+	}
+	if puzzles, gerr := ParseGrids(block); gerr == nil && len(puzzles) > 1 {
+		// The block's label goes to the first grid. Each grid is taken
+		// to span the same number of lines, decoration included.
+		for i, p := range puzzles {
+			s.emit(p.ToString(), start+i*len(block)/len(puzzles))
+		}
+		return
+	}
+
+	// Consecutive 4x4 puzzles look like an unfinished grid block.
+	for _, line := range block {
+		if SizeFor(len(line)) == 0 {
+			if s.OnSkip != nil {
+				s.OnSkip(start, err)
+			}
+			s.header = ""
+			return
+		}
+	}
+	for i, line := range block {
+		s.emit(line, start+i)
+	}
+}
@@ -0,0 +1,186 @@
+package sudoku
+
+import (
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// spaceGrid is the easy puzzle written one row per line with spaces for
+// blanks, including in the first and last columns.
+var spaceGrid = []string{
+	"53  7    ",
+	"6  195   ",
+	" 98    6 ",
+	"8   6   3",
+	"4  8 3  1",
+	"7   2   6",
+	" 6    28 ",
+	"   419  5",
+	"    8  79",
+}
+
+func TestScannerSpaceBlanks(t *testing.T) {
+	want := benchPuzzles[0].puzzle
+	input := "# easy\r\n" + strings.Join(spaceGrid, "\r\n") + "\r\n\r\n" + strings.ReplaceAll(want, ".", " ") + "\n"
+	sc := NewScanner(strings.NewReader(input))
+	var got []string
+	for sc.Scan() {
+		got = append(got, sc.Text())
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("Scanner read %d puzzles, want 2: %q", len(got), got)
+	}
+	for i, text := range got {
+		p, err := ParsePuzzle(text)
+		if err != nil {
+			t.Fatalf("puzzle %d: %v", i+1, err)
+		}
+		if p.ToString() != want {
+			t.Errorf("puzzle %d = %s, want %s", i+1, p.ToString(), want)
+		}
+	}
+}
+
+func TestParseSDKSpaceBlanks(t *testing.T) {
+	p, err := ParseSDK("#Aeasy\n#Bspaces for blanks\n" + strings.Join(spaceGrid, "\n") + "\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := p.ToString(), benchPuzzles[0].puzzle; got != want {
+		t.Errorf("ParseSDK = %s, want %s", got, want)
+	}
+}
+
+// gridLines writes a 9x9 puzzle one row per line, with Simple Sudoku box
+// decoration if decorated is set.
+func gridLines(puzzle string, decorated bool) []string {
+	var lines []string
+	for i := 0; i < SIZE; i++ {
+		row := puzzle[i*SIZE : (i+1)*SIZE]
+		if !decorated {
+			lines = append(lines, row)
+			continue
+		}
+		if i > 0 && i%3 == 0 {
+			lines = append(lines, "---+---+---")
+		}
+		lines = append(lines, row[:3]+"|"+row[3:6]+"|"+row[6:])
+	}
+	return lines
+}
+
+func TestScanner(t *testing.T) {
+	easy, hard := knownSolutions[0].puzzle, knownSolutions[2].puzzle
+	lines := func(parts ...[]string) string {
+		var all []string
+		for _, p := range parts {
+			all = append(all, p...)
+		}
+		return strings.Join(all, "\n") + "\n"
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  []scanned
+		skips int
+	}{
+		{
+			"headers",
+			lines([]string{"# first", easy, "% second", hard, "Grid 03"}, gridLines(easy, false)),
+			[]scanned{{easy, 2, "first"}, {hard, 4, "second"}, {easy, 6, "Grid 03"}},
+			0,
+		},
+		{
+			"decorated grid",
+			lines(gridLines(hard, true)),
+			[]scanned{{hard, 1, ""}},
+			0,
+		},
+		{
+			"stacked decorated grids",
+			lines([]string{"Grid 01"}, gridLines(easy, true), gridLines(hard, true)),
+			[]scanned{{easy, 2, "Grid 01"}, {hard, 13, ""}},
+			0,
+		},
+		{
+			"stacked plain grids",
+			lines(gridLines(hard, false), gridLines(easy, false)),
+			[]scanned{{hard, 1, ""}, {easy, 10, ""}},
+			0,
+		},
+		{
+			"consecutive 4x4 puzzles",
+			lines([]string{"1234341221434321", strings.Repeat(".", 16)}),
+			[]scanned{{"1234341221434321", 1, ""}, {strings.Repeat(".", 16), 2, ""}},
+			0,
+		},
+		{
+			"skipped line",
+			lines([]string{"# dropped", "not a puzzle", easy}),
+			[]scanned{{easy, 3, ""}},
+			1,
+		},
+	}
+	for _, tt := range tests {
+		sc := NewScanner(strings.NewReader(tt.input))
+		skips := 0
+		sc.OnSkip = func(int, error) { skips++ }
+		var got []scanned
+		for sc.Scan() {
+			got = append(got, scanned{sc.Text(), sc.Line(), sc.Label()})
+		}
+		if err := sc.Err(); err != nil {
+			t.Fatal(err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("%s: Scanner read %+v, want %+v", tt.name, got, tt.want)
+		}
+		if skips != tt.skips {
+			t.Errorf("%s: OnSkip called %d times, want %d", tt.name, skips, tt.skips)
+		}
+	}
+}
+
+func TestParseMarked(t *testing.T) {
+	empty := strings.Repeat(".", 15)
+	tests := []struct {
+		name  string
+		input string
+		want  []int // candidates at (0, 0); nil when the input is invalid
+	}{
+		{"digits", "{14}" + empty, []int{1, 4}},
+		{"commas", "{1, 4}" + empty, []int{1, 4}},
+		{"forbidden", "{^14}" + empty, []int{2, 3}},
+		{"whitespace", "{23}\n" + strings.Repeat(". ", 15), []int{2, 3}},
+		{"given", "1" + empty, []int{}},
+		{"unclosed", "{14" + empty, nil},
+		{"empty set", "{}" + empty, nil},
+		{"bad candidate", "{1x}" + empty, nil},
+		{"out of range", "{5}" + empty, nil},
+		{"all forbidden", "{^1234}" + empty, nil},
+		{"wrong size", "{12}" + empty[1:], nil},
+	}
+	for _, tt := range tests {
+		p, err := ParseMarked(tt.input)
+		if tt.want == nil {
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("%s: ParseMarked(%q) error = %v, want ErrInvalid", tt.name, tt.input, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: ParseMarked(%q): %v", tt.name, tt.input, err)
+			continue
+		}
+		got, err := p.Possibilities(0, 0)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("%s: Possibilities(0, 0) = %v, %v, want %v", tt.name, got, err, tt.want)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+package sudoku
+
+import (
+	"math/bits"
+	"math/rand"
+)
+
+// CellSelector picks the empty cell the search branches on next and returns
+// its candidate mask, or ok == false once the board has no empty cells. A
+// cell with no candidates may be returned to signal a dead end.
+type CellSelector func(p *Puzzle) (row, col int, poss uint32, ok bool)
+
+// MinRemaining is the default selector: the first empty cell, in row-major
+// order, with the fewest candidates.
+func MinRemaining(p *Puzzle) (row, col int, poss uint32, ok bool) {
+	return p.findBestCell()
+}
+
+// RandomMinRemaining returns a selector that, like MinRemaining, picks a
+// cell with the fewest candidates, but chooses uniformly among ties using
+// rng. A selector from a seeded rng makes the search reproducible.
+func RandomMinRemaining(rng *rand.Rand) CellSelector {
+	return func(p *Puzzle) (int, int, uint32, bool) {
+		best, ties := -1, 0
+		var bestPoss uint32
+		minCount := p.size + 1
+		for idx, val := range p.cells {
+			if val != 0 {
+				continue
+			}
+			poss := p.getPossibilities(idx/p.size, idx%p.size)
+			count := bits.OnesCount32(poss)
+			switch {
+			case count < minCount:
+				minCount, ties = count, 1
+			case count == minCount:
+				ties++
+				if rng.Intn(ties) != 0 {
+					continue
+				}
+			default:
+				continue
+			}
+			best, bestPoss = idx, poss
+			if count == 0 {
+				break
+			}
+		}
+		if best < 0 {
+			return 0, 0, 0, false
+		}
+		return best / p.size, best % p.size, bestPoss, true
+	}
+}
+
+// SetCellSelector replaces the heuristic used to choose the cell to branch
+// on. Passing nil restores MinRemaining.
+func (p *Puzzle) SetCellSelector(sel CellSelector) {
+	p.selector = sel
+}
+
+func (p *Puzzle) selectCell() (int, int, uint32, bool) {
+	if p.selector != nil {
+		return p.selector(p)
+	}
+	return p.findBestCell()
+}
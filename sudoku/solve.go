@@ -0,0 +1,247 @@
+package sudoku
+
+import (
+	"context"
+	"math/bits"
+	"math/rand"
+)
+
+// checkInterval is how many search nodes are visited between checks of the
+// context passed to SolveContext.
+const checkInterval = 1024
+
+// search carries the state of one solve across the recursion.
+type search struct {
+	ctx      context.Context
+	rng      *rand.Rand
+	maxNodes int // 0 for no limit
+	nodes    int
+	depth    int
+	err      error
+	stats    Stats
+}
+
+// nextDigit picks the digit to try next from the candidates in poss.
+func (s *search) nextDigit(poss uint32) int {
+	return pickDigit(s.rng, poss)
+}
+
+// pickDigit returns the lowest digit in poss, or a random one when rng is
+// not nil.
+func pickDigit(rng *rand.Rand, poss uint32) int {
+	if rng != nil {
+		for n := rng.Intn(bits.OnesCount32(poss)); n > 0; n-- {
+			poss &= poss - 1
+		}
+	}
+	return bits.TrailingZeros32(poss) + 1
+}
+
+// interrupted counts a node and reports whether the search should stop. The
+// context is checked at the first node, so a solve started after ctx is done
+// stops at once, and then every checkInterval nodes.
+func (s *search) interrupted() bool {
+	s.nodes++
+	if s.err == nil && s.maxNodes > 0 && s.nodes > s.maxNodes {
+		s.err = ErrNodeLimit
+	}
+	if s.err == nil && (s.nodes == 1 || s.nodes%checkInterval == 0) {
+		s.err = s.ctx.Err()
+	}
+	return s.err != nil
+}
+
+func (p *Puzzle) findBestCell() (int, int, uint32, bool) {
+	if p.emptyCell == 0 {
+		return 0, 0, 0, false
+	}
+
+	minRow, minCol := 0, 0
+	minPoss := p.allBits
+	minCount := p.size + 1
+
+	for w, word := range p.free[:(p.numCells+63)/64] {
+		for ; word != 0; word &= word - 1 {
+			idx := w*64 + bits.TrailingZeros64(word)
+			i, j := idx/p.size, idx%p.size
+			poss := p.getPossibilities(i, j)
+			count := bits.OnesCount32(poss)
+			if count < minCount {
+				minCount = count
+				minPoss = poss
+				minRow = i
+				minCol = j
+				if count <= 1 {
+					return minRow, minCol, minPoss, true
+				}
+			}
+		}
+	}
+	return minRow, minCol, minPoss, true
+}
+
+// SetRandom makes the search try the candidates of each cell in an order
+// drawn from rng rather than lowest first, so puzzles with several
+// solutions yield varied ones from Solve and SolveAll. Passing a seeded rng
+// keeps runs reproducible; nil restores the lowest-first default.
+func (p *Puzzle) SetRandom(rng *rand.Rand) {
+	p.rng = rng
+}
+
+// SetNodeLimit makes Solve and the solvers built on it give up once a single
+// solve has visited more than n search nodes, leaving the puzzle as it was.
+// SolveContext, SolveE and SolveString then report ErrNodeLimit. Unlike a
+// timeout this does not depend on the speed or load of the machine. n <= 0
+// removes the limit.
+func (p *Puzzle) SetNodeLimit(n int) {
+	p.maxNodes = max(n, 0)
+}
+
+// Solve fills the puzzle in place and reports whether a solution was found.
+func (p *Puzzle) Solve() bool {
+	return p.solve()
+}
+
+// Solution solves a copy of the puzzle and returns it, leaving the receiver
+// untouched. The copy is only meaningful when ok is true.
+func (p *Puzzle) Solution() (solved *Puzzle, ok bool) {
+	solved = p.Clone()
+	return solved, solved.solve()
+}
+
+func (p *Puzzle) solve() bool {
+	ok, _ := p.SolveContext(context.Background())
+	return ok
+}
+
+// SolveContext is like Solve but gives up once ctx is cancelled or its
+// deadline passes, returning ctx.Err(), or once the node limit set by
+// SetNodeLimit is passed, returning ErrNodeLimit. An interrupted search is
+// unwound so the puzzle is left as it was before the call.
+func (p *Puzzle) SolveContext(ctx context.Context) (bool, error) {
+	s := p.newSearch(ctx)
+	if p.run(s) {
+		return true, nil
+	}
+	return false, s.err
+}
+
+// newSearch returns the state for one solve of p bounded by ctx.
+func (p *Puzzle) newSearch(ctx context.Context) *search {
+	return &search{ctx: ctx, rng: p.rng, maxNodes: p.maxNodes}
+}
+
+// run searches from the current board, keeping the solution if one is found,
+// and records the node count in s.stats.
+func (p *Puzzle) run(s *search) bool {
+	ok := p.search(s)
+	s.stats.Nodes = s.nodes
+	if ok {
+		p.commit()
+	}
+	return ok
+}
+
+func (p *Puzzle) search(s *search) bool {
+	if s.interrupted() {
+		return false
+	}
+
+	s.depth++
+	if s.depth > s.stats.MaxDepth {
+		s.stats.MaxDepth = s.depth
+	}
+
+	mark, empty := len(p.trail), p.emptyCell
+	ok := p.propagate()
+	s.stats.Propagated += empty - p.emptyCell
+	if !ok {
+		p.undo(mark)
+		s.depth--
+		return false
+	}
+
+	row, col, poss, found := p.selectCell()
+	if !found {
+		s.depth--
+		return true
+	}
+
+	for poss != 0 {
+		digit := s.nextDigit(poss)
+		val := byte(digit)
+		p.setCell(row, col, val)
+		p.record(row, col, val, Guess)
+		s.stats.Guesses++
+
+		if p.search(s) {
+			s.depth--
+			return true
+		}
+		p.clearCell(row, col, val)
+		p.record(row, col, val, Backtrack)
+		s.stats.Backtracks++
+		if s.err != nil {
+			break
+		}
+		poss &^= 1 << (digit - 1)
+	}
+	p.undo(mark)
+	s.depth--
+	return false
+}
+
+// CountSolutions counts the distinct solutions of the puzzle, stopping once
+// limit have been found. Pass 2 to tell a unique puzzle from an ambiguous one.
+// The puzzle is left in its original state.
+func (p *Puzzle) CountSolutions(limit int) int {
+	return p.eachSolution(limit, func() {})
+}
+
+// SolveAll returns up to limit distinct solutions of the puzzle, each an
+// independent copy. The puzzle is left in its original state.
+func (p *Puzzle) SolveAll(limit int) []*Puzzle {
+	var solutions []*Puzzle
+	p.eachSolution(limit, func() {
+		solved := p.Clone()
+		clear(solved.elim)
+		solutions = append(solutions, solved)
+	})
+	return solutions
+}
+
+// eachSolution calls found with the board filled in for each solution, up
+// to limit of them, and returns how many it found. The board is restored
+// afterwards.
+func (p *Puzzle) eachSolution(limit int, found func()) int {
+	if limit <= 0 {
+		return 0
+	}
+	count := 0
+	p.enumerate(&count, limit, found)
+	return count
+}
+
+func (p *Puzzle) enumerate(count *int, limit int, onSolution func()) {
+	mark := len(p.trail)
+	defer p.undo(mark)
+	if !p.propagate() {
+		return
+	}
+
+	row, col, poss, found := p.selectCell()
+	if !found {
+		*count++
+		onSolution()
+		return
+	}
+
+	for poss != 0 && *count < limit {
+		digit := pickDigit(p.rng, poss)
+		val := byte(digit)
+		p.setCell(row, col, val)
+		p.enumerate(count, limit, onSolution)
+		p.clearCell(row, col, val)
+		poss &^= 1 << (digit - 1)
+	}
+}
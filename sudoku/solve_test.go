@@ -0,0 +1,263 @@
+package sudoku
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// knownSolutions pairs well-known puzzles with their unique solutions.
+var knownSolutions = []struct {
+	name     string
+	puzzle   string
+	solution string
+}{
+	{
+		"easy",
+		"53..7....6..195....98....6.8...6...34..8.3..17...2...6.6....28....419..5....8..79",
+		"534678912672195348198342567859761423426853791713924856961537284287419635345286179",
+	},
+	{
+		"17-clue",
+		"..............1..234.....5..6..3............1..7..2..8....5.46........3.8.9......",
+		"196524783785361942342798156968137524423985671517642398271853469654219837839476215",
+	},
+	{
+		"inkala",
+		"8..........36......7..9.2...5...7.......457.....1...3...1....68..85...1..9....4..",
+		"812753649943682175675491283154237896369845721287169534521974368438526917796318452",
+	},
+	{
+		"ai-escargot",
+		"1....7.9..3..2...8..96..5....53..9...1..8...26....4...3......1..4......7..7...3..",
+		"162857493534129678789643521475312986913586742628794135356478219241935867897261354",
+	},
+	{
+		"norvig-hard1",
+		"4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......",
+		"417369825632158947958724316825437169791586432346912758289643571573291684164875293",
+	},
+	{
+		"16x16",
+		"1.34...8.AB...F.....9A.C.E..1.....B.D.....3..6..DE.G.2.45678.ABC23.....9A.CDEF.1...9..CDE.G..34.AB.DE.G.23..6...E..1.34..7.9AB..34..7..A...E.G.278...CDE.G1........EF..2..5.7.9.F..23...78.AB.DE..6.8.AB.....1.38..BCD.FG1..4...CDEF.1..4.....A..1234.6.89..CD.F",
+		"123456789ABCDEFG56789ABCDEFG12349ABCDEFG12345678DEFG123456789ABC23456789ABCDEFG16789ABCDEFG12345ABCDEFG123456789EFG123456789ABCD3456789ABCDEFG12789ABCDEFG123456BCDEFG123456789AFG123456789ABCDE456789ABCDEFG12389ABCDEFG1234567CDEFG123456789ABG123456789ABCDEF",
+	},
+	{
+		"solved",
+		"534678912672195348198342567859761423426853791713924856961537284287419635345286179",
+		"534678912672195348198342567859761423426853791713924856961537284287419635345286179",
+	},
+}
+
+func TestSolveStringKnownSolutions(t *testing.T) {
+	for _, tt := range knownSolutions {
+		got, err := SolveString(tt.puzzle)
+		if err != nil {
+			t.Errorf("%s: SolveString: %v", tt.name, err)
+			continue
+		}
+		if got != tt.solution {
+			t.Errorf("%s: SolveString = %s, want %s", tt.name, got, tt.solution)
+		}
+	}
+}
+
+func TestSolveDLXKnownSolutions(t *testing.T) {
+	for _, tt := range knownSolutions {
+		p, err := ParsePuzzle(tt.puzzle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !p.SolveDLX() {
+			t.Errorf("%s: SolveDLX found no solution", tt.name)
+			continue
+		}
+		if got := p.ToString(); got != tt.solution {
+			t.Errorf("%s: SolveDLX = %s, want %s", tt.name, got, tt.solution)
+		}
+	}
+
+	// Row 1 needs a 9 that column 9 already holds.
+	input := "12345678.........9..............................................................."
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.SolveDLX() {
+		t.Errorf("SolveDLX solved %s", input)
+	}
+	if got := p.ToString(); got != input {
+		t.Errorf("SolveDLX changed an unsolvable board to %s", got)
+	}
+}
+
+func TestSolveContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	input := knownSolutions[0].puzzle
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok, err := p.SolveContext(ctx); ok || !errors.Is(err, context.Canceled) {
+		t.Errorf("SolveContext with a cancelled context = %v, %v, want false, context.Canceled", ok, err)
+	}
+	if got := p.ToString(); got != input {
+		t.Errorf("SolveContext with a cancelled context left %s", got)
+	}
+	if ok, err := p.SolveContext(context.Background()); !ok || err != nil {
+		t.Errorf("SolveContext = %v, %v, want true, nil", ok, err)
+	}
+}
+
+func TestSolveStringNoSolution(t *testing.T) {
+	// Row 1 needs a 9 that column 9 already holds.
+	_, err := SolveString("12345678.........9...............................................................")
+	if !errors.Is(err, ErrNoSolution) {
+		t.Fatalf("SolveString error = %v, want ErrNoSolution", err)
+	}
+}
+
+func TestForbid(t *testing.T) {
+	// The easy puzzle has a unique solution with a 4 at row 1, column 3, so
+	// forbidding it there leaves nothing to find.
+	input := benchPuzzles[0].puzzle
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Forbid(0, 2, 4); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SolveE(); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("SolveE after Forbid = %v, want ErrNoSolution", err)
+	}
+
+	marked, err := ParseMarked(input[:2] + "{^4}" + input[3:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if marked.SolveDLX() {
+		t.Error("SolveDLX solved a board with the only fitting digit forbidden")
+	}
+
+	marked, err = ParseMarked(input[:2] + "{^12356789}" + input[3:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := marked.Possibilities(0, 2)
+	if err != nil || len(got) != 1 || got[0] != 4 {
+		t.Errorf("Possibilities(0, 2) = %v, %v, want [4]", got, err)
+	}
+}
+
+func TestNakedSingles(t *testing.T) {
+	p, err := ParsePuzzle(benchPuzzles[0].puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	solution, err := SolveString(benchPuzzles[0].puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	singles := p.NakedSingles()
+	if len(singles) == 0 {
+		t.Fatal("NakedSingles() found none on the easy puzzle")
+	}
+	for _, s := range singles {
+		if want := int(solution[s[0]*SIZE+s[1]] - '0'); s[2] != want {
+			t.Errorf("NakedSingles() gives %d at (%d, %d), solution has %d", s[2], s[0], s[1], want)
+		}
+	}
+}
+
+func TestDifficulty(t *testing.T) {
+	tests := []struct {
+		puzzle string
+		label  string
+		err    error
+	}{
+		{knownSolutions[0].puzzle, "easy", nil},
+		// Row 1 needs a 9 that column 9 already holds.
+		{"12345678.........9...............................................................", "", ErrNoSolution},
+		{"11" + strings.Repeat(".", 79), "", ErrInvalid},
+	}
+	for _, tt := range tests {
+		p, err := ParsePuzzle(tt.puzzle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, label, err := p.Difficulty(); label != tt.label || !errors.Is(err, tt.err) {
+			t.Errorf("%s: Difficulty = %q, %v, want %q, %v", tt.puzzle, label, err, tt.label, tt.err)
+		}
+	}
+}
+
+func TestCountSolutions(t *testing.T) {
+	tests := []struct {
+		puzzle string
+		limit  int
+		want   int
+	}{
+		{knownSolutions[0].puzzle, 2, 1},
+		{knownSolutions[0].puzzle, 0, 0},
+		// An empty 4x4 board has 288 solutions.
+		{strings.Repeat(".", 16), 5, 5},
+		{strings.Repeat(".", 16), 1000, 288},
+		// Row 1 needs a 9 that column 9 already holds.
+		{"12345678.........9...............................................................", 2, 0},
+	}
+	for _, tt := range tests {
+		p, err := ParsePuzzle(tt.puzzle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// PencilGrid shows the candidates left in every empty cell.
+		before := p.PencilGrid()
+		if got := p.CountSolutions(tt.limit); got != tt.want {
+			t.Errorf("%s: CountSolutions(%d) = %d, want %d", tt.puzzle, tt.limit, got, tt.want)
+		}
+		if got := p.PencilGrid(); got != before {
+			t.Errorf("%s: CountSolutions(%d) changed the board to\n%s", tt.puzzle, tt.limit, got)
+		}
+	}
+}
+
+func TestSolveAll(t *testing.T) {
+	p, err := NewPuzzle(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	before := p.PencilGrid()
+	solutions := p.SolveAll(5)
+	if len(solutions) != 5 {
+		t.Fatalf("SolveAll(5) on an empty 4x4 board gave %d solutions", len(solutions))
+	}
+	if got := p.PencilGrid(); got != before {
+		t.Errorf("SolveAll changed the board to\n%s", got)
+	}
+
+	seen := map[string]bool{}
+	for _, s := range solutions {
+		if !s.IsSolvedCorrectly() {
+			t.Errorf("SolveAll gave an invalid solution %s", s.ToString())
+		}
+		seen[s.ToString()] = true
+	}
+	if len(seen) != len(solutions) {
+		t.Errorf("SolveAll gave %d distinct solutions out of %d", len(seen), len(solutions))
+	}
+
+	// Emptying one solution must not touch the others or the puzzle.
+	want := solutions[1].ToString()
+	if err := solutions[0].Set(0, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if got := solutions[1].ToString(); got != want {
+		t.Errorf("editing one solution changed another to %s", got)
+	}
+	if got := p.PencilGrid(); got != before {
+		t.Errorf("editing a solution changed the puzzle to\n%s", got)
+	}
+}
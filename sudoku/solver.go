@@ -0,0 +1,49 @@
+package sudoku
+
+import "context"
+
+// Solver solves puzzles one after another, reusing its buffers between them
+// so large batches do not allocate a board per puzzle. The zero value is
+// ready to use. A Solver must not be used from several goroutines at once.
+type Solver struct {
+	scratch Puzzle
+
+	// setup, if set, is applied to each board after it is loaded.
+	setup func(*Puzzle) error
+}
+
+// SolveInto parses input as ParsePuzzle does, validates it and solves it
+// into dst, reusing dst's buffers when it already holds a board of the same
+// size. It reports false if the puzzle is malformed, breaks the rules or has
+// no solution, in which case the contents of dst are unspecified.
+func (s *Solver) SolveInto(input string, dst *Puzzle) bool {
+	if dst.Load(input) != nil || dst.Validate() != nil {
+		return false
+	}
+	return dst.solve()
+}
+
+// Solve is like SolveInto but solves into a board owned by s and returns
+// the solution as a string.
+func (s *Solver) Solve(input string) (string, bool) {
+	solution, _, err := s.solveString(context.Background(), input)
+	return solution, err == nil
+}
+
+// solveString is Solve reporting why it failed, as SolveString does, and
+// the work the search did, with the search bounded by ctx.
+func (s *Solver) solveString(ctx context.Context, input string) (string, Stats, error) {
+	if err := s.scratch.Load(input); err != nil {
+		return "", Stats{}, err
+	}
+	if s.setup != nil {
+		if err := s.setup(&s.scratch); err != nil {
+			return "", Stats{}, err
+		}
+	}
+	state := s.scratch.newSearch(ctx)
+	if err := s.scratch.solveE(state); err != nil {
+		return "", state.stats, err
+	}
+	return s.scratch.ToString(), state.stats, nil
+}
@@ -0,0 +1,32 @@
+package sudoku
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Stats describes the work done by a single solve.
+type Stats struct {
+	Nodes      int           // search nodes visited
+	Propagated int           // cells filled by singles propagation
+	Guesses    int           // digits tried by the backtracking search
+	Backtracks int           // guesses that were undone
+	MaxDepth   int           // deepest level of the search
+	Elapsed    time.Duration // wall-clock time of the solve
+}
+
+func (s Stats) String() string {
+	return fmt.Sprintf("nodes=%d propagated=%d guesses=%d backtracks=%d depth=%d elapsed=%v",
+		s.Nodes, s.Propagated, s.Guesses, s.Backtracks, s.MaxDepth, s.Elapsed)
+}
+
+// SolveStats solves the puzzle in place like Solve and reports the work the
+// search did. The counters cover only this call.
+func (p *Puzzle) SolveStats() (Stats, bool) {
+	s := p.newSearch(context.Background())
+	start := time.Now()
+	ok := p.run(s)
+	s.stats.Elapsed = time.Since(start)
+	return s.stats, ok
+}
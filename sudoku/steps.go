@@ -0,0 +1,37 @@
+package sudoku
+
+// Technique names the reason a step was taken.
+type Technique string
+
+const (
+	NakedSingle  Technique = "naked single"
+	HiddenSingle Technique = "hidden single"
+	Guess        Technique = "guess"
+	Backtrack    Technique = "backtrack"
+)
+
+// Step is one change to the board made while solving. Backtrack steps clear
+// Digit from the cell again; every other technique places it.
+type Step struct {
+	Row       int
+	Col       int
+	Digit     int
+	Technique Technique
+}
+
+// SolveWithSteps solves the puzzle in place like Solve and also returns every
+// placement and removal made along the way. Replaying the steps in order on
+// the original board reproduces the board at any point of the search.
+func (p *Puzzle) SolveWithSteps() ([]Step, bool) {
+	steps := []Step{}
+	p.steps = &steps
+	defer func() { p.steps = nil }()
+	ok := p.solve()
+	return steps, ok
+}
+
+func (p *Puzzle) record(row, col int, val byte, technique Technique) {
+	if p.steps != nil {
+		*p.steps = append(*p.steps, Step{Row: row, Col: col, Digit: int(val), Technique: technique})
+	}
+}
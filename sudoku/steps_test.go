@@ -0,0 +1,42 @@
+package sudoku
+
+import "testing"
+
+func TestSolveWithStepsReplay(t *testing.T) {
+	for _, bp := range benchPuzzles {
+		p, err := ParsePuzzle(bp.puzzle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		steps, ok := p.SolveWithSteps()
+		if !ok {
+			t.Fatalf("%s: no solution", bp.name)
+		}
+
+		// Set refuses givens and clashing digits, so every step must be a
+		// legal move on the board as the steps before it left it.
+		replay, _ := ParsePuzzle(bp.puzzle)
+		counts := map[Technique]int{}
+		for n, s := range steps {
+			counts[s.Technique]++
+			digit := s.Digit
+			if s.Technique == Backtrack {
+				if got, _ := replay.At(s.Row, s.Col); got != s.Digit {
+					t.Fatalf("%s: step %d backtracks %d at (%d, %d), which holds %d", bp.name, n, s.Digit, s.Row, s.Col, got)
+				}
+				digit = 0
+			} else if got, _ := replay.At(s.Row, s.Col); got != 0 {
+				t.Fatalf("%s: step %d places %d at (%d, %d), which holds %d", bp.name, n, s.Digit, s.Row, s.Col, got)
+			}
+			if err := replay.Set(s.Row, s.Col, digit); err != nil {
+				t.Fatalf("%s: step %d %+v: %v", bp.name, n, s, err)
+			}
+		}
+		if got, want := replay.ToString(), p.ToString(); got != want {
+			t.Errorf("%s: replayed steps give %s, SolveWithSteps gave %s", bp.name, got, want)
+		}
+		if bp.name != "easy" && (counts[Guess] == 0 || counts[Backtrack] == 0) {
+			t.Errorf("%s: steps have %d guesses and %d backtracks, want some of each", bp.name, counts[Guess], counts[Backtrack])
+		}
+	}
+}
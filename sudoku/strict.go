@@ -0,0 +1,28 @@
+package sudoku
+
+import "fmt"
+
+// StrictPolicy lists the checks ParseStrict makes on top of ParsePuzzle, for
+// pipelines that must tell genuine puzzles from pasted answers.
+type StrictPolicy struct {
+	// RejectSolved refuses boards with no empty cells.
+	RejectSolved bool
+	// MaxClues, if positive, refuses boards with more givens than this.
+	MaxClues int
+}
+
+// ParseStrict is ParsePuzzle followed by the checks in policy. A board that
+// fails one is reported with an error wrapping ErrInvalid.
+func ParseStrict(input string, policy StrictPolicy) (*Puzzle, error) {
+	p, err := ParsePuzzle(input)
+	if err != nil {
+		return nil, err
+	}
+	if policy.RejectSolved && p.emptyCell == 0 {
+		return nil, fmt.Errorf("%w: board has no empty cells", ErrInvalid)
+	}
+	if policy.MaxClues > 0 && p.ClueCount() > policy.MaxClues {
+		return nil, fmt.Errorf("%w: board has %d givens, more than the %d allowed", ErrInvalid, p.ClueCount(), policy.MaxClues)
+	}
+	return p, nil
+}
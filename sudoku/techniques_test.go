@@ -0,0 +1,108 @@
+package sudoku
+
+import (
+	"reflect"
+	"testing"
+)
+
+// removed returns, for every cell whose candidates shrank between before
+// and after, the candidates it lost.
+func removed(before, after [][]uint32) map[[2]int]uint32 {
+	lost := map[[2]int]uint32{}
+	for i := range before {
+		for j := range before[i] {
+			if gone := before[i][j] &^ after[i][j]; gone != 0 {
+				lost[[2]int{i, j}] = gone
+			}
+		}
+	}
+	return lost
+}
+
+// checkElimination runs one pass of a technique on an empty board whose
+// candidates have been narrowed with Forbid, where forbid[d] lists the
+// cells that may not hold digit d, and compares what it removes with want.
+func checkElimination(t *testing.T, forbid map[int][][2]int, run func(p *Puzzle) bool, want map[[2]int]uint32) {
+	t.Helper()
+	p, err := NewPuzzle(SIZE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for d, cells := range forbid {
+		for _, c := range cells {
+			if err := p.Forbid(c[0], c[1], d); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	before := p.Candidates()
+	if !run(p) {
+		t.Error("nothing eliminated")
+	}
+	if got := removed(before, p.Candidates()); !reflect.DeepEqual(got, want) {
+		t.Errorf("eliminated %v, want %v", got, want)
+	}
+}
+
+// checkSameSolution solves every known puzzle with a technique switched on
+// by set and checks the solution is unchanged.
+func checkSameSolution(t *testing.T, set func(p *Puzzle)) {
+	t.Helper()
+	for _, tt := range knownSolutions {
+		p, err := ParsePuzzle(tt.puzzle)
+		if err != nil {
+			t.Fatal(err)
+		}
+		set(p)
+		if !p.Solve() {
+			t.Errorf("%s: no solution", tt.name)
+			continue
+		}
+		if got := p.ToString(); got != tt.solution {
+			t.Errorf("%s: Solve = %s, want %s", tt.name, got, tt.solution)
+		}
+	}
+}
+
+// rowCells returns the cells of row i in the given columns.
+func rowCells(i int, cols ...int) [][2]int {
+	var cells [][2]int
+	for _, j := range cols {
+		cells = append(cells, [2]int{i, j})
+	}
+	return cells
+}
+
+func TestHiddenPairs(t *testing.T) {
+	// 1 and 2 fit only in the first two cells of row 1, so those cells
+	// lose every other digit.
+	rest := rowCells(0, 2, 3, 4, 5, 6, 7, 8)
+	others := uint32(ALL_BITS &^ 0b11)
+	checkElimination(t, map[int][][2]int{1: rest, 2: rest}, (*Puzzle).eliminateHiddenPairs,
+		map[[2]int]uint32{{0, 0}: others, {0, 1}: others})
+	checkSameSolution(t, func(p *Puzzle) { p.SetHiddenPairs(true) })
+}
+
+func TestBoxLineReduction(t *testing.T) {
+	// In box 1, 1 fits only in row 1, so the rest of row 1 loses it.
+	notRow1 := append(rowCells(1, 0, 1, 2), rowCells(2, 0, 1, 2)...)
+	want := map[[2]int]uint32{}
+	for _, c := range rowCells(0, 3, 4, 5, 6, 7, 8) {
+		want[c] = 1
+	}
+	checkElimination(t, map[int][][2]int{1: notRow1}, (*Puzzle).eliminateIntersections, want)
+	checkSameSolution(t, func(p *Puzzle) { p.SetBoxLineReduction(true) })
+}
+
+func TestFish(t *testing.T) {
+	// In rows 1 and 5, 1 fits only in columns 1 and 5: an X-wing that
+	// removes 1 from the rest of those columns.
+	outside := append(rowCells(0, 1, 2, 3, 5, 6, 7, 8), rowCells(4, 1, 2, 3, 5, 6, 7, 8)...)
+	want := map[[2]int]uint32{}
+	for _, i := range []int{1, 2, 3, 5, 6, 7, 8} {
+		want[[2]int{i, 0}] = 1
+		want[[2]int{i, 4}] = 1
+	}
+	checkElimination(t, map[int][][2]int{1: outside}, func(p *Puzzle) bool { return p.eliminateFish(2) }, want)
+	checkSameSolution(t, func(p *Puzzle) { p.SetFish(3) })
+}
@@ -0,0 +1,137 @@
+package sudoku
+
+import "fmt"
+
+// unitKind records which setting added an extra unit, so that turning one
+// variant off leaves the others in place.
+type unitKind int
+
+const (
+	diagonalUnit unitKind = iota
+	windowUnit
+	customUnit
+)
+
+// extraUnit is a unit beyond the rows, columns and boxes of the layout, such
+// as an X-Sudoku diagonal, with the digits placed in it so far.
+type extraUnit struct {
+	kind  unitKind
+	name  string // for Validate errors, such as "the main diagonal"
+	cells []int
+	used  uint32
+}
+
+// Unit is a group of N cells that must hold every digit exactly once, like
+// a row, column or box. Cells are (row, col) pairs.
+type Unit [][2]int
+
+// SetDiagonal turns the X-Sudoku constraint on or off. When on, each of the
+// two main diagonals must also hold every digit exactly once.
+func (p *Puzzle) SetDiagonal(on bool) {
+	var units []extraUnit
+	if on {
+		main := extraUnit{kind: diagonalUnit, name: "the main diagonal"}
+		anti := extraUnit{kind: diagonalUnit, name: "the anti-diagonal"}
+		for i := 0; i < p.size; i++ {
+			main.cells = append(main.cells, i*p.size+i)
+			anti.cells = append(anti.cells, i*p.size+p.size-1-i)
+		}
+		units = []extraUnit{main, anti}
+	}
+	p.setExtras(diagonalUnit, units)
+}
+
+// SetWindoku turns the Windoku (Hyper Sudoku) constraint on or off. When
+// on, the box-shaped windows set one cell in from every box boundary, four
+// of them on a 9x9 board, must also hold every digit exactly once.
+func (p *Puzzle) SetWindoku(on bool) {
+	var units []extraUnit
+	if on {
+		for r := 1; r+p.boxRows < p.size; r += p.boxRows + 1 {
+			for c := 1; c+p.boxCols < p.size; c += p.boxCols + 1 {
+				u := extraUnit{kind: windowUnit, name: fmt.Sprintf("window %d", len(units)+1)}
+				for i := r; i < r+p.boxRows; i++ {
+					for j := c; j < c+p.boxCols; j++ {
+						u.cells = append(u.cells, i*p.size+j)
+					}
+				}
+				units = append(units, u)
+			}
+		}
+	}
+	p.setExtras(windowUnit, units)
+}
+
+// AddUnit adds a constraint that the given N cells, which must be distinct,
+// hold every digit exactly once. It returns an error wrapping ErrInvalid if
+// the cells do not form such a group; clashes between givens are left to
+// Validate.
+func (p *Puzzle) AddUnit(unit Unit) error {
+	n := len(p.extrasOf(customUnit)) + 1
+	if len(unit) != p.size {
+		return fmt.Errorf("%w: unit %d has %d cells, want %d", ErrInvalid, n, len(unit), p.size)
+	}
+	u := extraUnit{kind: customUnit, name: fmt.Sprintf("unit %d", n)}
+	seen := make(map[int]bool, p.size)
+	for _, cell := range unit {
+		row, col := cell[0], cell[1]
+		if row < 0 || row >= p.size || col < 0 || col >= p.size {
+			return fmt.Errorf("%w: unit %d has cell (%d, %d) outside the grid", ErrInvalid, n, row, col)
+		}
+		idx := row*p.size + col
+		if seen[idx] {
+			return fmt.Errorf("%w: unit %d has cell (%d, %d) twice", ErrInvalid, n, row, col)
+		}
+		seen[idx] = true
+		u.cells = append(u.cells, idx)
+	}
+	p.setExtras(customUnit, append(p.extrasOf(customUnit), u))
+	return nil
+}
+
+// extrasOf returns a copy of the extra units of the given kind.
+func (p *Puzzle) extrasOf(kind unitKind) []extraUnit {
+	var units []extraUnit
+	for _, u := range p.extras {
+		if u.kind == kind {
+			units = append(units, u)
+		}
+	}
+	return units
+}
+
+// setExtras replaces the extra units of the given kind with units and
+// rebuilds the indexes over them. The slices are built afresh rather than
+// edited, since clones share them.
+func (p *Puzzle) setExtras(kind unitKind, units []extraUnit) {
+	var extras []extraUnit
+	for _, u := range p.extras {
+		if u.kind != kind {
+			extras = append(extras, u)
+		}
+	}
+	p.extras = append(extras, units...)
+	p.indexExtras()
+}
+
+// indexExtras recomputes the digits placed in each extra unit, extraOf and
+// allUnits after the extra units or the layout change.
+func (p *Puzzle) indexExtras() {
+	if len(p.extras) == 0 {
+		p.extras, p.extraOf, p.allUnits = nil, nil, nil
+		return
+	}
+	p.extraOf = make([][]int, p.numCells)
+	p.allUnits = append([][]int{}, p.units...)
+	for n := range p.extras {
+		u := &p.extras[n]
+		u.used = 0
+		for _, idx := range u.cells {
+			if val := p.cells[idx]; val != 0 {
+				u.used |= 1 << (val - 1)
+			}
+			p.extraOf[idx] = append(p.extraOf[idx], n)
+		}
+		p.allUnits = append(p.allUnits, u.cells)
+	}
+}
@@ -0,0 +1,70 @@
+package sudoku
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAddUnitRejects(t *testing.T) {
+	diagonal := func() Unit {
+		var u Unit
+		for i := 0; i < SIZE; i++ {
+			u = append(u, [2]int{i, i})
+		}
+		return u
+	}
+	tests := []struct {
+		name string
+		unit Unit
+	}{
+		{"too few cells", diagonal()[1:]},
+		{"too many cells", append(diagonal(), [2]int{0, 8})},
+		{"repeated cell", append(diagonal()[1:], [2]int{1, 1})},
+		{"outside", append(diagonal()[1:], [2]int{SIZE, 0})},
+		{"negative", append(diagonal()[1:], [2]int{0, -1})},
+	}
+	for _, tt := range tests {
+		p, err := NewPuzzle(SIZE)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.AddUnit(tt.unit); !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: AddUnit error = %v, want ErrInvalid", tt.name, err)
+		}
+		if p.extras != nil {
+			t.Errorf("%s: rejected unit was added", tt.name)
+		}
+	}
+}
+
+func TestAddUnitConstrains(t *testing.T) {
+	var diagonal Unit
+	for i := 0; i < SIZE; i++ {
+		diagonal = append(diagonal, [2]int{i, i})
+	}
+
+	p, err := NewPuzzle(SIZE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddUnit(diagonal); err != nil {
+		t.Fatal(err)
+	}
+	if !p.Solve() {
+		t.Fatal("no solution for an empty board with one extra unit")
+	}
+	checkUnits(t, p, "unit", [][]int{{0, 10, 20, 30, 40, 50, 60, 70, 80}})
+
+	// The easy puzzle's only solution repeats 7 on the main diagonal, so
+	// the unit leaves it without one.
+	p, err = ParsePuzzle(knownSolutions[0].puzzle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AddUnit(diagonal); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SolveE(); !errors.Is(err, ErrNoSolution) {
+		t.Errorf("SolveE with the unit = %v, want ErrNoSolution", err)
+	}
+}
@@ -0,0 +1,57 @@
+package sudoku
+
+import "fmt"
+
+// Variant names a kind of board by the units its digits must fill.
+type Variant int
+
+const (
+	// Classic has rows, columns and boxes only.
+	Classic Variant = iota
+	// XSudoku adds the two main diagonals, as SetDiagonal does.
+	XSudoku
+	// Windoku adds the windows of SetWindoku.
+	Windoku
+	// Jigsaw replaces the boxes with irregular regions, which SetRegions
+	// supplies.
+	Jigsaw
+)
+
+var variantNames = [...]string{"classic", "x", "windoku", "jigsaw"}
+
+func (v Variant) String() string {
+	if v < 0 || int(v) >= len(variantNames) {
+		return "unknown"
+	}
+	return variantNames[v]
+}
+
+// ParseVariant returns the variant with the given name, as printed by
+// String.
+func ParseVariant(name string) (Variant, error) {
+	for v, n := range variantNames {
+		if n == name {
+			return Variant(v), nil
+		}
+	}
+	return Classic, fmt.Errorf("sudoku: unknown variant %q", name)
+}
+
+// SetVariant switches the board to v, replacing the diagonals and windows
+// of any variant set before; units added with AddUnit are kept. Jigsaw keeps
+// the regions from SetRegions, so it may come before or after that call,
+// and the other variants restore classic boxes. An unknown variant is an
+// error and leaves the board unchanged.
+func (p *Puzzle) SetVariant(v Variant) error {
+	switch v {
+	case Classic, XSudoku, Windoku, Jigsaw:
+	default:
+		return fmt.Errorf("sudoku: unknown variant %d", int(v))
+	}
+	if v != Jigsaw && p.layout != layouts[p.size] {
+		p.SetRegions(nil)
+	}
+	p.SetDiagonal(v == XSudoku)
+	p.SetWindoku(v == Windoku)
+	return nil
+}
@@ -0,0 +1,150 @@
+package sudoku
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseVariant(t *testing.T) {
+	for _, v := range []Variant{Classic, XSudoku, Windoku, Jigsaw} {
+		got, err := ParseVariant(v.String())
+		if err != nil || got != v {
+			t.Errorf("ParseVariant(%q) = %v, %v, want %v", v.String(), got, err, v)
+		}
+	}
+	if _, err := ParseVariant("samurai"); err == nil {
+		t.Error("ParseVariant accepted an unknown name")
+	}
+}
+
+// checkUnits reports an error for every unit of cells whose digits repeat.
+func checkUnits(t *testing.T, p *Puzzle, name string, units [][]int) {
+	t.Helper()
+	for n, unit := range units {
+		var seen uint32
+		for _, idx := range unit {
+			val := p.cells[idx]
+			if val == 0 || seen&(1<<(val-1)) != 0 {
+				t.Errorf("%s %d repeats or misses a digit:\n%s", name, n+1, p)
+				break
+			}
+			seen |= 1 << (val - 1)
+		}
+	}
+}
+
+func TestSetVariant(t *testing.T) {
+	var diagonals [2][]int
+	for i := 0; i < SIZE; i++ {
+		diagonals[0] = append(diagonals[0], i*SIZE+i)
+		diagonals[1] = append(diagonals[1], i*SIZE+SIZE-1-i)
+	}
+	var windows [][]int
+	for _, top := range []int{1, 5} {
+		for _, left := range []int{1, 5} {
+			var w []int
+			for i := top; i < top+3; i++ {
+				for j := left; j < left+3; j++ {
+					w = append(w, i*SIZE+j)
+				}
+			}
+			windows = append(windows, w)
+		}
+	}
+
+	tests := []struct {
+		variant Variant
+		name    string
+		units   [][]int
+	}{
+		{XSudoku, "diagonal", diagonals[:]},
+		{Windoku, "window", windows},
+	}
+	for _, tt := range tests {
+		p, err := NewPuzzle(SIZE)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := p.SetVariant(tt.variant); err != nil {
+			t.Fatalf("SetVariant(%v): %v", tt.variant, err)
+		}
+		if !p.Solve() {
+			t.Fatalf("%v: no solution for an empty board", tt.variant)
+		}
+		checkUnits(t, p, tt.name, tt.units)
+		if err := p.Validate(); err != nil {
+			t.Errorf("%v: Validate: %v", tt.variant, err)
+		}
+	}
+
+	// A 1 at both ends of the main diagonal is only wrong in X-Sudoku.
+	p, err := ParsePuzzle("1" + strings.Repeat(".", 79) + "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetVariant(XSudoku); err != nil {
+		t.Fatal(err)
+	}
+	if p.Validate() == nil {
+		t.Error("X-Sudoku board with a repeated diagonal digit passed Validate")
+	}
+	for _, v := range []Variant{Classic, Jigsaw} {
+		if err := p.SetVariant(v); err != nil {
+			t.Errorf("SetVariant(%v): %v", v, err)
+		}
+		if err := p.Validate(); err != nil {
+			t.Errorf("%v: Validate still sees the diagonal: %v", v, err)
+		}
+	}
+
+	// A failed call must leave the X-Sudoku rules in place.
+	if err := p.SetVariant(XSudoku); err != nil {
+		t.Fatal(err)
+	}
+	before := p.Clone()
+	if err := p.SetVariant(Variant(99)); err == nil {
+		t.Error("SetVariant accepted an unknown variant")
+	}
+	if !reflect.DeepEqual(p, before) {
+		t.Error("SetVariant(99) changed the board")
+	}
+	if p.Validate() == nil {
+		t.Error("SetVariant(99) turned off the diagonals")
+	}
+}
+
+func TestSetVariantKeepsRegions(t *testing.T) {
+	p, err := NewPuzzle(SIZE)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := p.SetRegions(regionsFromMap(jigsawMap)); err != nil {
+		t.Fatal(err)
+	}
+	p.SetDiagonal(true)
+	if err := p.SetVariant(Jigsaw); err != nil {
+		t.Fatal(err)
+	}
+	if p.extras != nil {
+		t.Error("SetVariant(Jigsaw) kept the diagonals")
+	}
+	if !p.Solve() {
+		t.Fatal("no solution for an empty jigsaw board")
+	}
+	regions := make([][]int, SIZE)
+	for i, row := range jigsawMap {
+		for j, ch := range row {
+			r := int(ch - '0')
+			regions[r] = append(regions[r], i*SIZE+j)
+		}
+	}
+	checkUnits(t, p, "region", regions)
+
+	if err := p.SetVariant(Classic); err != nil {
+		t.Fatal(err)
+	}
+	if p.layout != layouts[SIZE] {
+		t.Error("SetVariant(Classic) kept the jigsaw regions")
+	}
+}
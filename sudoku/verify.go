@@ -0,0 +1,44 @@
+package sudoku
+
+// IsSolvedCorrectly reports whether the board is a complete, valid
+// solution: no empty cells and no digit repeated in a row, column or box,
+// nor on a diagonal or in a cage when those constraints are set. Unlike
+// Solve it only checks the board as it stands.
+func (p *Puzzle) IsSolvedCorrectly() bool {
+	if p.emptyCell != 0 || p.Validate() != nil {
+		return false
+	}
+	if p.cageOf == nil {
+		return true
+	}
+	used := make([]uint32, len(p.cages))
+	totals := make([]int, len(p.cages))
+	for idx, n := range p.cageOf {
+		bit := uint32(1) << (p.cells[idx] - 1)
+		if used[n]&bit != 0 {
+			return false
+		}
+		used[n] |= bit
+		totals[n] += int(p.cells[idx])
+	}
+	for n, c := range p.cages {
+		if totals[n] != c.sum {
+			return false
+		}
+	}
+	return true
+}
+
+// CheckAgainst reports whether the board is a correct solution of givens:
+// it must pass IsSolvedCorrectly and keep every clue of givens unchanged.
+func (p *Puzzle) CheckAgainst(givens *Puzzle) bool {
+	if givens.size != p.size || !p.IsSolvedCorrectly() {
+		return false
+	}
+	for idx, val := range givens.cells {
+		if val != 0 && p.cells[idx] != val {
+			return false
+		}
+	}
+	return true
+}